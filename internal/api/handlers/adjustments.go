@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Admin API for manual balance adjustments and audit trail.
+type HandlerAdjustment struct {
+	adjSrv *services.AdjustmentService
+	conf   *config.Config
+}
+
+func NewHandlerAdjustment(conf *config.Config, adjSrv *services.AdjustmentService) *HandlerAdjustment {
+	return &HandlerAdjustment{adjSrv: adjSrv, conf: conf}
+}
+
+// Credit (positive sum) or debit (negative sum) user's bonuses.
+func (u *HandlerAdjustment) AddAdjustment(res http.ResponseWriter, req *http.Request) {
+	admin, ok := req.Context().Value(entities.CtxAdminKey{}).(string)
+	if !ok {
+		errt := "Cat't get admin from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	userID, err := uuid.FromString(chi.URLParam(req, "userID"))
+	if err != nil {
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
+		return
+	}
+
+	var ar entities.AdjustmentRequest
+	if err := json.NewDecoder(req.Body).Decode(&ar); err != nil {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adj := entities.NewAdjustment(userID, &ar, admin)
+	if !adj.IsValid() {
+		// 422
+		errt := "Adjustment not valid: sum must be non zero, reason and note are required."
+		zap.S().Debugln(errt, ar)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	err = u.adjSrv.AddAdjustment(req.Context(), adj)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrUserNotFound):
+			// 404
+			http.Error(res, "User not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrNotEnoughBonuses):
+			// 409
+			http.Error(res, "Not enuogh bonuses.", http.StatusConflict)
+		default:
+			// 500
+			errt := "Error during adjustment creation."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Adjustment ", adj.AdjustmentID, " status ", adj.Status, " created by ", admin)
+
+	// 202 if adjustment waits for approval
+	status := http.StatusCreated
+	if adj.Status == entities.AWAITING {
+		status = http.StatusAccepted
+	}
+	writeJSON(res, adj, status)
+}
+
+// List adjustments, filter by status (?status=PENDING).
+func (u *HandlerAdjustment) GetAdjustments(res http.ResponseWriter, req *http.Request) {
+	status := entities.AdjustmentStatus(req.URL.Query().Get("status"))
+	adjustments, err := u.adjSrv.GetAdjustments(req.Context(), status)
+	if err != nil {
+		// 500
+		errt := "Cat't get adjustments."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(adjustments) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, adjustments, http.StatusOK)
+}
+
+func (u *HandlerAdjustment) Approve(res http.ResponseWriter, req *http.Request) {
+	u.decide(res, req, true)
+}
+
+func (u *HandlerAdjustment) Reject(res http.ResponseWriter, req *http.Request) {
+	u.decide(res, req, false)
+}
+
+// Audit trail of admins' actions, filter by user (?user=uuid).
+func (u *HandlerAdjustment) GetAudit(res http.ResponseWriter, req *http.Request) {
+	var userID *uuid.UUID
+	if user := req.URL.Query().Get("user"); user != "" {
+		id, err := uuid.FromString(user)
+		if err != nil {
+			// 400
+			http.Error(res, "Wrong user id.", http.StatusBadRequest)
+			return
+		}
+		userID = &id
+	}
+
+	audit, err := u.adjSrv.GetAudit(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Cat't get audit log."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(audit) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, audit, http.StatusOK)
+}
+
+func (u *HandlerAdjustment) decide(res http.ResponseWriter, req *http.Request, approve bool) {
+	admin, ok := req.Context().Value(entities.CtxAdminKey{}).(string)
+	if !ok {
+		errt := "Cat't get admin from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	adjustmentID, err := uuid.FromString(chi.URLParam(req, "adjustmentID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Adjustment not found.", http.StatusNotFound)
+		return
+	}
+
+	var adj *entities.Adjustment
+	if approve {
+		adj, err = u.adjSrv.Approve(req.Context(), adjustmentID, admin)
+	} else {
+		adj, err = u.adjSrv.Reject(req.Context(), adjustmentID, admin)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrAdjustmentNotFound):
+			// 404
+			http.Error(res, "Adjustment not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrSelfApproval):
+			// 403
+			http.Error(res, "Adjustment must be approved by another admin.", http.StatusForbidden)
+		case errors.Is(err, entities.ErrAdjustmentDecided):
+			// 409
+			http.Error(res, "Adjustment alredy decided.", http.StatusConflict)
+		case errors.Is(err, entities.ErrNotEnoughBonuses):
+			// 409
+			http.Error(res, "Not enuogh bonuses.", http.StatusConflict)
+		default:
+			// 500
+			errt := "Error during adjustment decision."
+			zap.S().Errorln(errt, adjustmentID, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Adjustment ", adjustmentID, " ", adj.Status, " by ", admin)
+	writeJSON(res, adj, http.StatusOK)
+}
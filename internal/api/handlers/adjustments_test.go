@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAdjustment(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		status     entities.AdjustmentStatus
+		addError   error
+		statusCode int
+	}{
+		{
+			name:       "Goodwill credit applied",
+			body:       `{"sum": 100, "reason": "GOODWILL", "note": "Late delivery"}`,
+			calls:      1,
+			status:     entities.APPLIED,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Debit applied",
+			body:       `{"sum": -50, "reason": "CORRECTION", "note": "Double accrual"}`,
+			calls:      1,
+			status:     entities.APPLIED,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Big credit waits for approval",
+			body:       `{"sum": 5000, "reason": "COMPLAINT", "note": "Lost order"}`,
+			calls:      1,
+			status:     entities.AWAITING,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Debit more than balance (409)",
+			body:       `{"sum": -500, "reason": "FRAUD", "note": "Fake orders"}`,
+			calls:      1,
+			status:     entities.APPLIED,
+			addError:   entities.ErrNotEnoughBonuses,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Unknown reason (422)",
+			body:       `{"sum": 100, "reason": "GIFT", "note": "Gift"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Without note (422)",
+			body:       `{"sum": 100, "reason": "GOODWILL"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	conf.ApprovalThreshold = decimal.NewFromFloat(1000)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoAdj := mocks.NewMockAdjustmentRepo(ctrl)
+			adjSrv := services.NewAdjustmentService(repoAdj, conf.ApprovalThreshold)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoAdj.EXPECT().
+				AddAdjustment(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, adj *entities.Adjustment) error {
+					assert.Equal(t, tt.status, adj.Status)
+					assert.Equal(t, userID, adj.UserID)
+					return tt.addError
+				})
+
+			// add chi context with user id
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userID", userID.String())
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/users/"+userID.String()+"/adjustments", strings.NewReader(tt.body))
+
+			// add admin to context
+			ctxAdmin := context.WithValue(req.Context(), entities.CtxAdminKey{}, "admin")
+			req = req.WithContext(context.WithValue(ctxAdmin, chi.RouteCtxKey, rctx))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			adjHand := NewHandlerAdjustment(conf, adjSrv)
+			adjHand.AddAdjustment(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestApproveAdjustment(t *testing.T) {
+	tests := []struct {
+		name         string
+		adjustmentID string
+		decideError  error
+		statusCode   int
+	}{
+		{
+			name:         "Approve by second admin",
+			adjustmentID: uuid.Must(uuid.NewV4()).String(),
+			statusCode:   http.StatusOK,
+		},
+		{
+			name:         "Approve by author (403)",
+			adjustmentID: uuid.Must(uuid.NewV4()).String(),
+			decideError:  entities.ErrSelfApproval,
+			statusCode:   http.StatusForbidden,
+		},
+		{
+			name:         "Approve decided adjustment (409)",
+			adjustmentID: uuid.Must(uuid.NewV4()).String(),
+			decideError:  entities.ErrAdjustmentDecided,
+			statusCode:   http.StatusConflict,
+		},
+		{
+			name:         "Approve not existed adjustment (404)",
+			adjustmentID: uuid.Must(uuid.NewV4()).String(),
+			decideError:  entities.ErrAdjustmentNotFound,
+			statusCode:   http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoAdj := mocks.NewMockAdjustmentRepo(ctrl)
+			adjSrv := services.NewAdjustmentService(repoAdj, conf.ApprovalThreshold)
+
+			var adj *entities.Adjustment
+			if tt.decideError == nil {
+				adj = &entities.Adjustment{AdjustmentID: uuid.FromStringOrNil(tt.adjustmentID), Status: entities.APPLIED}
+			}
+
+			_ = repoAdj.EXPECT().
+				DecideAdjustment(gomock.Any(), uuid.FromStringOrNil(tt.adjustmentID), "approver", true).
+				Times(1).
+				Return(adj, tt.decideError)
+
+			// add chi context with adjustment id
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("adjustmentID", tt.adjustmentID)
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/adjustments/"+tt.adjustmentID+"/approve", nil)
+
+			// add admin to context
+			ctxAdmin := context.WithValue(req.Context(), entities.CtxAdminKey{}, "approver")
+			req = req.WithContext(context.WithValue(ctxAdmin, chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			adjHand := NewHandlerAdjustment(conf, adjSrv)
+			adjHand.Approve(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err := res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Write value as JSON answer.
+func writeJSON(res http.ResponseWriter, v any, status int) {
+	jsonV, err := json.Marshal(v)
+	if err != nil {
+		errt := "Error during Marshal answer"
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// set content type
+	res.Header().Add("Content-Type", "application/json")
+
+	res.WriteHeader(status)
+
+	_, err = res.Write(jsonV)
+	if err != nil {
+		zap.S().Errorln("Can't write JSON to response", err)
+	}
+}
+
+// Write 422 with reason if order number is rejected, true if number is valid.
+func writeOrderError(res http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	var orderErr *entities.OrderError
+	if errors.As(err, &orderErr) {
+		zap.S().Debugln("Order number rejected: ", orderErr)
+		writeJSON(res, orderErr, http.StatusUnprocessableEntity)
+		return false
+	}
+	// 422
+	http.Error(res, err.Error(), http.StatusUnprocessableEntity)
+	return false
+}
+
+// Write 400 with field errors if request is rejected by validation, false for other errors.
+func writeValidationError(res http.ResponseWriter, err error) bool {
+	var valErr *entities.ValidationError
+	if !errors.As(err, &valErr) {
+		return false
+	}
+	zap.S().Debugln("Request rejected: ", valErr)
+	writeJSON(res, valErr, http.StatusBadRequest)
+	return true
+}
+
+// Write 422 with violation code if withdrawal is rejected by policy, true if withdrawal is allowed.
+func writePolicyError(res http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	var policyErr *entities.PolicyError
+	if errors.As(err, &policyErr) {
+		zap.S().Debugln("Withdrawal policy violation: ", policyErr)
+		writeJSON(res, policyErr, http.StatusUnprocessableEntity)
+		return false
+	}
+	// 500
+	errt := "Error cheking withdrawal policy."
+	zap.S().Errorln(errt, err)
+	http.Error(res, errt, http.StatusInternalServerError)
+	return false
+}
+
+// Write 429 with Retry-After if attempt must wait or 500 on error, true if attempt is allowed.
+func writeThrottled(res http.ResponseWriter, message string, wait time.Duration, err error) bool {
+	if err != nil {
+		// 500
+		errt := "Can't check login attempts."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return false
+	}
+	if wait > 0 {
+		// 429
+		res.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(res, message, http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
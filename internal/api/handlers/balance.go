@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
-	"github.com/ShiraazMoollatjie/goluhn"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
 	"github.com/shopspring/decimal"
 
 	"github.com/shulganew/gophermart/internal/app/config"
@@ -41,26 +44,51 @@ func (u *HandlerBalance) GetBalance(res http.ResponseWriter, req *http.Request)
 
 	userID := ctxConfig.GetUserID()
 
-	bonuses, err := u.calcSrv.GetBonuses(req.Context(), userID)
+	u.writeBalance(res, req, userID)
+}
+
+// Admin API: balance of any user.
+func (u *HandlerBalance) GetUserBalance(res http.ResponseWriter, req *http.Request) {
+	userID, err := uuid.FromString(chi.URLParam(req, "userID"))
 	if err != nil {
-		// 500
-		errt := "Cat't get bonuses."
-		zap.S().Errorln(errt, err)
-		http.Error(res, errt, http.StatusInternalServerError)
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
 		return
 	}
 
-	withdrawn, err := u.calcSrv.GetWithdrawn(req.Context(), userID)
+	u.writeBalance(res, req, userID)
+}
+
+// Write current balance or balance at time from query (?at=RFC3339).
+func (u *HandlerBalance) writeBalance(res http.ResponseWriter, req *http.Request, userID uuid.UUID) {
+	var userBalance *entities.UserBalance
+	var err error
+	if at := req.URL.Query().Get("at"); at != "" {
+		t, errp := time.Parse(time.RFC3339, at)
+		if errp != nil {
+			// 400
+			errt := "Wrong time format, use RFC3339."
+			zap.S().Debugln(errt, at)
+			http.Error(res, errt, http.StatusBadRequest)
+			return
+		}
+		userBalance, err = u.calcSrv.GetBalanceAt(req.Context(), userID, t)
+	} else {
+		userBalance, err = u.calcSrv.GetBalance(req.Context(), userID)
+	}
+	if errors.Is(err, entities.ErrUserNotFound) {
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		// 500
-		errt := "Cat't get withdrawn."
+		errt := "Cat't get balance."
 		zap.S().Errorln(errt, err)
 		http.Error(res, errt, http.StatusInternalServerError)
 		return
 	}
 
-	userBalance := entities.NewUserBalance(bonuses, withdrawn)
-
 	jsonBalance, err := json.Marshal(userBalance)
 	if err != nil {
 		http.Error(res, "Error during Marshal user's balance", http.StatusInternalServerError)
@@ -105,16 +133,23 @@ func (u *HandlerBalance) SetWithdraw(res http.ResponseWriter, req *http.Request)
 
 	zap.S().Debugln("Withdrawn order: ", wd.OrderNr)
 	zap.S().Debugln("Withdrawn amount: ", wd.Withdrawn)
-	err := goluhn.Validate(wd.OrderNr)
-	if err != nil {
+	if !writeOrderError(res, u.orderSrv.ValidateOrder(wd.OrderNr)) {
+		return
+	}
+
+	amount := decimal.NewFromFloat(wd.Withdrawn)
+	if !amount.IsPositive() {
 		// 422
-		errt := "Order not luna valid."
-		zap.S().Debugln(errt, wd.OrderNr)
+		errt := "Withdrawal sum must be positive."
+		zap.S().Debugln(errt, wd.Withdrawn)
 		http.Error(res, errt, http.StatusUnprocessableEntity)
 		return
 	}
 
-	amount := decimal.NewFromFloat(wd.Withdrawn)
+	// Check withdrawal limits.
+	if !writePolicyError(res, u.calcSrv.CheckPolicy(req.Context(), userID, amount, decimal.NewFromFloat(wd.OrderSum))) {
+		return
+	}
 
 	isEnough, err := u.calcSrv.CheckBalance(req.Context(), userID, amount)
 	if err != nil {
@@ -132,15 +167,15 @@ func (u *HandlerBalance) SetWithdraw(res http.ResponseWriter, req *http.Request)
 
 	// Create preorder with withdrawal and add storage with mark preoreder bool = true
 	order := entities.NewOrder(userID, wd.OrderNr, true, amount, decimal.Zero)
-	existed, err := u.orderSrv.IsExist(req.Context(), order.OrderNr)
+	outcome, err := u.orderSrv.AddOrder(req.Context(), true, order)
 	if err != nil {
 		// 500
-		errt := "Error during withdraw. Checking order duplication error."
+		errt := "Error during withdraw. Adding preorder error."
 		zap.S().Debugln(errt, wd.OrderNr, err)
 		http.Error(res, errt, http.StatusInternalServerError)
 		return
 	}
-	if existed {
+	if outcome != entities.UploadAccepted {
 		// 422
 		errt := "Order alredy existed."
 		zap.S().Debugln(errt, wd.OrderNr)
@@ -148,17 +183,14 @@ func (u *HandlerBalance) SetWithdraw(res http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	err = u.orderSrv.AddOrder(req.Context(), true, order)
-	if err != nil {
-		// 500
-		errt := "Error during withdraw. Adding preorder error."
-		zap.S().Debugln(errt, wd.OrderNr, err)
-		http.Error(res, errt, http.StatusInternalServerError)
+	// Update withdrawals and bonuses balance, daily and monthly limits are checked on debit.
+	err = u.calcSrv.MakeWithdrawn(req.Context(), userID, order.OrderNr, amount)
+	var policyErr *entities.PolicyError
+	if errors.As(err, &policyErr) {
+		// 422
+		writePolicyError(res, policyErr)
 		return
 	}
-
-	// Update withdrawals and bonuses balance.
-	err = u.calcSrv.MakeWithdrawn(req.Context(), userID, amount)
 	if err != nil {
 		// 500
 		errt := "Error during withdrawn."
@@ -225,3 +257,54 @@ func (u *HandlerBalance) GetWithdrawals(res http.ResponseWriter, req *http.Reque
 		zap.S().Errorln("Can't write to response in GetWithdrawals  handler", err)
 	}
 }
+
+// All movements of user's bonuses: accruals, withdrawals, holds and campaign credits.
+func (u *HandlerBalance) GetHistory(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	u.writeHistory(res, req, ctxConfig.GetUserID())
+}
+
+// Balance history of any user, admin only.
+func (u *HandlerBalance) GetUserHistory(res http.ResponseWriter, req *http.Request) {
+	userID, err := uuid.FromString(chi.URLParam(req, "userID"))
+	if err != nil {
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
+		return
+	}
+
+	u.writeHistory(res, req, userID)
+}
+
+func (u *HandlerBalance) writeHistory(res http.ResponseWriter, req *http.Request, userID uuid.UUID) {
+	history, err := u.calcSrv.GetHistory(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Cat't get balance history"
+		zap.S().Error(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+	if len(history) == 0 {
+		// 204 - no history
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, history, http.StatusOK)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gofrs/uuid"
@@ -114,13 +115,13 @@ func TestWithdraw(t *testing.T) {
 			repoCalc := mocks.NewMockCalcRepo(ctrl)
 			repoOrder := mocks.NewMockOrderRepo(ctrl)
 
-			register := services.NewUserService(repoUser)
-			calc := services.NewCalcService(repoCalc)
-			orderServ := services.NewOrderService(repoOrder)
+			register := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			calc := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+			orderServ := services.NewOrderService(repoOrder, entities.OrderValidator{})
 
-			uuid, err := uuid.NewV7()
+			id, err := uuid.NewV7()
 			assert.NoError(t, err)
-			user := entities.User{UUID: uuid, Login: "Test123", Password: "123456"}
+			user := entities.User{UUID: id, Login: "Test123", Password: "123456"}
 
 			_ = repoUser.EXPECT().
 				AddUser(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -130,15 +131,10 @@ func TestWithdraw(t *testing.T) {
 			_ = repoOrder.EXPECT().
 				AddOrder(gomock.Any(), gomock.Any()).
 				AnyTimes().
-				Return(nil)
-
-			_ = repoOrder.EXPECT().
-				IsExist(gomock.Any(), gomock.Any()).
-				AnyTimes().
-				Return(tt.orderIsExisted, nil)
+				Return(&entities.BatchOrder{Inserted: !tt.orderIsExisted, Owner: &user.UUID}, nil)
 
 			_ = repoCalc.EXPECT().
-				MakeWithdrawn(gomock.Any(), gomock.Any(), gomock.Any()).
+				MakeWithdrawn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				AnyTimes().
 				Return(nil)
 
@@ -175,7 +171,7 @@ func TestWithdraw(t *testing.T) {
 
 			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
 
-			jwt, _ := services.BuildJWTString(user.UUID, conf.PassJWT)
+			jwt, _ := services.BuildJWTString(user.UUID, uuid.Nil, entities.RoleUser, entities.NewHMACKeys(conf.PassJWT), time.Hour)
 
 			req.Header.Add("Authorization", jwt)
 			req.Header.Add("Content-Type", "application/json")
@@ -210,6 +206,7 @@ func TestBalance(t *testing.T) {
 		requestURL string
 		bonuses    decimal.Decimal
 		withdrawn  decimal.Decimal
+		held       decimal.Decimal
 		statusCode int
 	}{
 		{
@@ -217,6 +214,7 @@ func TestBalance(t *testing.T) {
 			requestURL: "http://localhost:8080/api/user/balance",
 			bonuses:    decimal.NewFromFloat(12.2),
 			withdrawn:  decimal.NewFromFloat(6.2),
+			held:       decimal.Zero,
 			statusCode: http.StatusOK,
 		},
 
@@ -225,6 +223,7 @@ func TestBalance(t *testing.T) {
 			requestURL: "http://localhost:8080/api/user/balance",
 			bonuses:    decimal.NewFromFloat(33.2),
 			withdrawn:  decimal.NewFromFloat(22.2),
+			held:       decimal.NewFromFloat(5.5),
 			statusCode: http.StatusOK,
 		},
 	}
@@ -252,13 +251,13 @@ func TestBalance(t *testing.T) {
 			repoCalc := mocks.NewMockCalcRepo(ctrl)
 			repoOrder := mocks.NewMockOrderRepo(ctrl)
 
-			userSrv := services.NewUserService(repoUser)
-			calcSrv := services.NewCalcService(repoCalc)
-			orderSrv := services.NewOrderService(repoOrder)
+			userSrv := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			calcSrv := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
 
-			uuid, err := uuid.NewV7()
+			id, err := uuid.NewV7()
 			assert.NoError(t, err)
-			user := entities.User{UUID: uuid, Login: "Test123", Password: "123"}
+			user := entities.User{UUID: id, Login: "Test123", Password: "123"}
 
 			_ = repoUser.EXPECT().
 				AddUser(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -275,6 +274,11 @@ func TestBalance(t *testing.T) {
 				Times(1).
 				Return(tt.withdrawn, nil)
 
+			_ = repoCalc.EXPECT().
+				GetHeld(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(tt.held, nil)
+
 			userID, exist, err := userSrv.CreateUser(ctx, user.Login, user.Password)
 			assert.NoError(t, err)
 			assert.False(t, exist)
@@ -288,7 +292,7 @@ func TestBalance(t *testing.T) {
 
 			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
 
-			jwt, _ := services.BuildJWTString(user.UUID, conf.PassJWT)
+			jwt, _ := services.BuildJWTString(user.UUID, uuid.Nil, entities.RoleUser, entities.NewHMACKeys(conf.PassJWT), time.Hour)
 
 			req.Header.Add("Authorization", jwt)
 			req.Header.Add("Content-Type", "text/plain")
@@ -318,15 +322,334 @@ func TestBalance(t *testing.T) {
 
 			b := decimal.NewFromFloat(balance.Bonus)
 			w := decimal.NewFromFloat(balance.Withdrawn)
+			h := decimal.NewFromFloat(balance.Held)
 
 			bt := tt.bonuses
 			wt := tt.withdrawn
 
 			assert.Equal(t, b.Equal(bt), true)
 			assert.Equal(t, w.Equal(wt), true)
+			assert.Equal(t, h.Equal(tt.held), true)
+
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestWithdrawPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     entities.WithdrawPolicy
+		amount     float64
+		orderSum   float64
+		withdrawn  decimal.Decimal
+		registered time.Time
+		code       string
+		statusCode int
+	}{
+		{
+			name:       "Below minimal withdrawal",
+			policy:     entities.WithdrawPolicy{Min: decimal.NewFromInt(10)},
+			amount:     5,
+			code:       entities.WithdrawBelowMin,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Above maximal withdrawal",
+			policy:     entities.WithdrawPolicy{Max: decimal.NewFromInt(100)},
+			amount:     150,
+			code:       entities.WithdrawAboveMax,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Daily limit exceeded",
+			policy:     entities.WithdrawPolicy{Daily: decimal.NewFromInt(100)},
+			amount:     50,
+			withdrawn:  decimal.NewFromInt(60),
+			code:       entities.DailyLimitExceeded,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Monthly limit exceeded",
+			policy:     entities.WithdrawPolicy{Monthly: decimal.NewFromInt(1000)},
+			amount:     50,
+			withdrawn:  decimal.NewFromInt(990),
+			code:       entities.MonthlyLimitExceeded,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Order share exceeded",
+			policy:     entities.WithdrawPolicy{Share: decimal.NewFromFloat(0.5)},
+			amount:     60,
+			orderSum:   100,
+			code:       entities.OrderShareExceeded,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Order sum required for share limit",
+			policy:     entities.WithdrawPolicy{Share: decimal.NewFromFloat(0.5)},
+			amount:     10,
+			code:       entities.OrderSumRequired,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Registration cool-down",
+			policy:     entities.WithdrawPolicy{Cooldown: time.Hour * 24},
+			amount:     10,
+			registered: time.Now().Add(-time.Hour),
+			code:       entities.RegistrationCooldown,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Negative sum without min limit",
+			amount:     -10,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "All limits passed",
+			policy: entities.WithdrawPolicy{Min: decimal.NewFromInt(1), Max: decimal.NewFromInt(100), Daily: decimal.NewFromInt(100),
+				Monthly: decimal.NewFromInt(1000), Share: decimal.NewFromFloat(0.5), Cooldown: time.Hour * 24},
+			amount:     10,
+			orderSum:   100,
+			withdrawn:  decimal.NewFromInt(20),
+			registered: time.Now().Add(-time.Hour * 48),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	conf.PassJWT = "JWTsecret"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoCalc := mocks.NewMockCalcRepo(ctrl)
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+
+			calc := services.NewCalcService(repoCalc, tt.policy, nil)
+			orderServ := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoCalc.EXPECT().
+				GetRegistered(gomock.Any(), userID).
+				AnyTimes().
+				Return(tt.registered, nil)
+
+			_ = repoCalc.EXPECT().
+				GetBonuses(gomock.Any(), userID).
+				AnyTimes().
+				Return(decimal.NewFromInt(1000), nil)
+
+			_ = repoOrder.EXPECT().
+				AddOrder(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(&entities.BatchOrder{Inserted: true, Owner: &userID}, nil)
+
+			// daily and monthly limits are checked by storage on debit
+			_ = repoCalc.EXPECT().
+				MakeWithdrawn(gomock.Any(), userID, gomock.Any(), gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, amount decimal.Decimal, limits entities.WithdrawLimits) error {
+					assert.True(t, limits.Daily.Equal(tt.policy.Daily))
+					assert.True(t, limits.Monthly.Equal(tt.policy.Monthly))
+					return limits.Check(tt.withdrawn.Add(amount), tt.withdrawn.Add(amount))
+				})
+
+			wd := entities.Withdraw{OrderNr: "7020147356", Withdrawn: tt.amount, OrderSum: tt.orderSum}
+			jsonWd, err := json.Marshal(wd)
+			require.NoError(t, err)
+
+			// add chi context
+			rctx := chi.NewRouteContext()
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/balance/withdraw", strings.NewReader(string(jsonWd)))
+
+			// add User and isRegister true tu context
+			ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			// Make request
+			balanceHand := NewHandlerBalance(conf, calc, orderServ)
+			balanceHand.SetWithdraw(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if tt.code != "" {
+				var policyErr entities.PolicyError
+				err = json.NewDecoder(res.Body).Decode(&policyErr)
+				require.NoError(t, err)
+				assert.Equal(t, tt.code, policyErr.Code)
+			}
+
+			err = res.Body.Close()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBalanceAt(t *testing.T) {
+	tests := []struct {
+		name       string
+		at         string
+		calls      int
+		bonuses    decimal.Decimal
+		withdrawn  decimal.Decimal
+		held       decimal.Decimal
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "Balance at March 1st",
+			at:         "2024-03-01T00:00:00Z",
+			calls:      1,
+			bonuses:    decimal.NewFromFloat(120.5),
+			withdrawn:  decimal.NewFromFloat(30),
+			held:       decimal.NewFromFloat(10),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Balance before registration",
+			at:         "2020-01-01T00:00:00+03:00",
+			calls:      1,
+			bonuses:    decimal.Zero,
+			withdrawn:  decimal.Zero,
+			held:       decimal.Zero,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Unknown user (404)",
+			at:         "2024-03-01T00:00:00Z",
+			calls:      1,
+			err:        entities.ErrUserNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Wrong time format (400)",
+			at:         "2024-03-01",
+			calls:      0,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoCalc := mocks.NewMockCalcRepo(ctrl)
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+
+			calcSrv := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			at, _ := time.Parse(time.RFC3339, tt.at)
+			_ = repoCalc.EXPECT().
+				GetBalanceAt(gomock.Any(), userID, at).
+				Times(tt.calls).
+				Return(tt.bonuses, tt.withdrawn, tt.held, tt.err)
+
+			// add chi context with user id for admin API
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userID", userID.String())
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/admin/users/"+userID.String()+"/balance", nil)
+			q := req.URL.Query()
+			q.Add("at", tt.at)
+			req.URL.RawQuery = q.Encode()
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			balanceHand := NewHandlerBalance(conf, calcSrv, orderSrv)
+			balanceHand.GetUserBalance(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
 
+			// check answer code
 			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
 			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var balance entities.UserBalance
+			err = json.NewDecoder(res.Body).Decode(&balance)
+			require.NoError(t, err)
+
+			assert.True(t, decimal.NewFromFloat(balance.Bonus).Equal(tt.bonuses))
+			assert.True(t, decimal.NewFromFloat(balance.Withdrawn).Equal(tt.withdrawn))
+			assert.True(t, decimal.NewFromFloat(balance.Held).Equal(tt.held))
 		})
 	}
 }
+
+func TestBalanceDebt(t *testing.T) {
+	app.InitLog()
+	conf := &config.Config{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// crete mock storege
+	repoCalc := mocks.NewMockCalcRepo(ctrl)
+	calcSrv := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+	orderSrv := services.NewOrderService(mocks.NewMockOrderRepo(ctrl), entities.OrderValidator{})
+
+	userID, err := uuid.NewV7()
+	assert.NoError(t, err)
+
+	// Returned order debited more than user had.
+	_ = repoCalc.EXPECT().GetBonuses(gomock.Any(), userID).Times(1).Return(decimal.NewFromFloat(-42.5), nil)
+	_ = repoCalc.EXPECT().GetWithdrawn(gomock.Any(), userID).Times(1).Return(decimal.NewFromFloat(100), nil)
+	_ = repoCalc.EXPECT().GetHeld(gomock.Any(), userID).Times(1).Return(decimal.Zero, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/user/balance", nil)
+	req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+
+	// create status recorder
+	resRecord := httptest.NewRecorder()
+
+	balanceHand := NewHandlerBalance(conf, calcSrv, orderSrv)
+	balanceHand.GetBalance(resRecord, req)
+
+	// get result
+	res := resRecord.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var balance entities.UserBalance
+	err = json.NewDecoder(res.Body).Decode(&balance)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.0, balance.Bonus)
+	assert.Equal(t, 42.5, balance.Debt)
+	assert.Equal(t, 100.0, balance.Withdrawn)
+}
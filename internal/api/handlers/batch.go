@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Bulk upload of orders: JSON array of numbers or text/csv with number in the first column.
+func (u *HandlerOrder) AddOrders(res http.ResponseWriter, req *http.Request) {
+	// Get UserID from cxt values.
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	req.Body = http.MaxBytesReader(res, req.Body, entities.MaxBatchBytes)
+	orders, err := readBatch(req)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || len(orders) > entities.MaxBatch {
+		// 413
+		http.Error(res, "Too many orders in upload.", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		// 400
+		errt := "Cat't read orders: " + err.Error()
+		zap.S().Debugln(errt)
+		http.Error(res, errt, http.StatusBadRequest)
+		return
+	}
+
+	results, err := u.orderSrv.AddOrders(req.Context(), userID, orders)
+	if err != nil {
+		// 500
+		errt := "Get error during save new orders."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Move prepaid preoreders to regular orders, as in single upload.
+	// Bulk upload has no purchase metadata, so orders are not registered in Accrual system.
+	for _, result := range results {
+		if !result.PreOrder {
+			continue
+		}
+		order := entities.NewOrder(userID, result.OrderNr, false, decimal.Zero, decimal.Zero)
+		err = u.calcSrv.MovePreOrder(req.Context(), order)
+		if err != nil {
+			errt := "Get error during preorder update."
+			zap.S().Errorln(errt, result.OrderNr, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	zap.S().Infoln("Bulk upload of ", len(orders), " orders for user: ", userID)
+	writeJSON(res, results, http.StatusOK)
+}
+
+// Read order numbers from JSON array or CSV body, CSV is read up to MaxBatch+1 orders.
+func readBatch(req *http.Request) ([]string, error) {
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	orders := make([]string, 0)
+	switch contentType {
+	case "application/json":
+		if err := json.NewDecoder(req.Body).Decode(&orders); err != nil {
+			return nil, err
+		}
+	case "text/csv":
+		reader := csv.NewReader(req.Body)
+		reader.FieldsPerRecord = -1
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			orderNr := strings.TrimSpace(record[0])
+			if orderNr == "" {
+				continue
+			}
+			orders = append(orders, orderNr)
+			if len(orders) > entities.MaxBatch {
+				break
+			}
+		}
+	default:
+		return nil, errors.New("content type must be application/json or text/csv")
+	}
+
+	if len(orders) == 0 {
+		return nil, errors.New("no orders")
+	}
+	return orders, nil
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ShiraazMoollatjie/goluhn"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOrders(t *testing.T) {
+	newOrder := goluhn.Generate(10)
+	userOrder := goluhn.Generate(10)
+	otherOrder := goluhn.Generate(10)
+	preOrder := goluhn.Generate(10)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		calls       int
+		results     []string
+		statusCode  int
+	}{
+		{
+			name:        "JSON upload",
+			contentType: "application/json",
+			body:        `["` + newOrder + `", "` + userOrder + `", "` + otherOrder + `", "12345", "` + newOrder + `"]`,
+			calls:       1,
+			results:     []string{entities.BatchAccepted, entities.BatchAlreadyYours, entities.BatchOtherUser, entities.BatchInvalid, entities.BatchAlreadyYours},
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:        "CSV upload with preorder",
+			contentType: "text/csv",
+			body:        newOrder + ",2024-03-01\n\n" + preOrder + ",2024-03-02\n",
+			calls:       1,
+			results:     []string{entities.BatchAccepted, entities.BatchAlreadyYours},
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:        "Only invalid orders",
+			contentType: "application/json",
+			body:        `["12345", "abc"]`,
+			calls:       0,
+			results:     []string{entities.BatchInvalid, entities.BatchInvalid},
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:        "Empty upload (400)",
+			contentType: "application/json",
+			body:        `[]`,
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "Too many CSV rows (413)",
+			contentType: "text/csv",
+			body:        strings.Repeat(newOrder+"\n", entities.MaxBatch+1),
+			statusCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "Too large body (413)",
+			contentType: "application/json",
+			body:        `["` + strings.Repeat("1", entities.MaxBatchBytes) + `"]`,
+			statusCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "Wrong content type (400)",
+			contentType: "text/plain",
+			body:        newOrder,
+			statusCode:  http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			repoCalc := mocks.NewMockCalcRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+			calcSrv := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+			otherID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoOrder.EXPECT().
+				AddOrders(gomock.Any(), userID, gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, _ uuid.UUID, orders []string) ([]entities.BatchOrder, error) {
+					batch := make([]entities.BatchOrder, 0)
+					for _, orderNr := range orders {
+						switch orderNr {
+						case newOrder:
+							batch = append(batch, entities.BatchOrder{OrderNr: orderNr, Inserted: true})
+						case userOrder:
+							batch = append(batch, entities.BatchOrder{OrderNr: orderNr, Owner: &userID})
+						case preOrder:
+							batch = append(batch, entities.BatchOrder{OrderNr: orderNr, Owner: &userID, IsPreOrder: true})
+						default:
+							batch = append(batch, entities.BatchOrder{OrderNr: orderNr, Owner: &otherID})
+						}
+					}
+					return batch, nil
+				})
+
+			// preorder moved to regular order
+			moves := 0
+			if strings.Contains(tt.body, preOrder) {
+				moves = 1
+			}
+			_ = repoCalc.EXPECT().
+				MovePreOrder(gomock.Any(), gomock.Any()).
+				Times(moves).
+				Return(nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/orders/batch", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+			req.Header.Add("Content-Type", tt.contentType)
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, calcSrv, nil, orderSrv)
+			ordersHand.AddOrders(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var results []entities.BatchResult
+			err = json.NewDecoder(res.Body).Decode(&results)
+			require.NoError(t, err)
+			require.Len(t, results, len(tt.results))
+			for i, result := range results {
+				assert.Equal(t, tt.results[i], result.Result, result.OrderNr)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Admin API for promotional campaigns.
+type HandlerCampaign struct {
+	campSrv *services.CampaignService
+	conf    *config.Config
+}
+
+func NewHandlerCampaign(conf *config.Config, campSrv *services.CampaignService) *HandlerCampaign {
+	return &HandlerCampaign{campSrv: campSrv, conf: conf}
+}
+
+func (u *HandlerCampaign) AddCampaign(res http.ResponseWriter, req *http.Request) {
+	var cr entities.CampaignRequest
+	if err := json.NewDecoder(req.Body).Decode(&cr); err != nil {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	campaign := entities.NewCampaign(&cr)
+	if !campaign.IsValid() {
+		// 422
+		errt := "Campaign not valid."
+		zap.S().Debugln(errt, cr)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	err := u.campSrv.AddCampaign(req.Context(), campaign)
+	if err != nil {
+		// 500
+		errt := "Error during campaign creation."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	zap.S().Infoln("Campaign ", campaign.Name, " created by ", req.Context().Value(entities.CtxAdminKey{}))
+	writeJSON(res, campaign, http.StatusCreated)
+}
+
+func (u *HandlerCampaign) GetCampaigns(res http.ResponseWriter, req *http.Request) {
+	campaigns, err := u.campSrv.GetCampaigns(req.Context())
+	if err != nil {
+		// 500
+		errt := "Cat't get campaigns."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(campaigns) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, campaigns, http.StatusOK)
+}
+
+func (u *HandlerCampaign) PauseCampaign(res http.ResponseWriter, req *http.Request) {
+	u.setPaused(res, req, true)
+}
+
+func (u *HandlerCampaign) ResumeCampaign(res http.ResponseWriter, req *http.Request) {
+	u.setPaused(res, req, false)
+}
+
+func (u *HandlerCampaign) GetReport(res http.ResponseWriter, req *http.Request) {
+	campaignID, err := uuid.FromString(chi.URLParam(req, "campaignID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Campaign not found.", http.StatusNotFound)
+		return
+	}
+
+	report, err := u.campSrv.GetReport(req.Context(), campaignID)
+	if err != nil {
+		if errors.Is(err, entities.ErrCampaignNotFound) {
+			// 404
+			http.Error(res, "Campaign not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Cat't get campaign report."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(res, report, http.StatusOK)
+}
+
+func (u *HandlerCampaign) setPaused(res http.ResponseWriter, req *http.Request, paused bool) {
+	campaignID, err := uuid.FromString(chi.URLParam(req, "campaignID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Campaign not found.", http.StatusNotFound)
+		return
+	}
+
+	err = u.campSrv.SetPaused(req.Context(), campaignID, paused)
+	if err != nil {
+		if errors.Is(err, entities.ErrCampaignNotFound) {
+			// 404
+			http.Error(res, "Campaign not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Error during campaign update."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	zap.S().Infoln("Campaign ", campaignID, " paused: ", paused, " by ", req.Context().Value(entities.CtxAdminKey{}))
+
+	// set status code 200
+	res.WriteHeader(http.StatusOK)
+
+	_, err = res.Write([]byte("Done."))
+	if err != nil {
+		zap.S().Errorln("Can't write to response in campaign handler", err)
+	}
+}
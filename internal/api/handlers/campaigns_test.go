@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCampaign(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Double points weekend",
+			body:       `{"name": "Double weekend", "kind": "MULTIPLIER", "value": 2, "starts_at": "2024-03-02T00:00:00Z", "ends_at": "2024-03-04T00:00:00Z"}`,
+			calls:      1,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "First order bonus",
+			body:       `{"name": "First order", "kind": "FIXED", "value": 100, "first_order": true, "stackable": true, "starts_at": "2024-03-01T00:00:00Z", "ends_at": "2024-04-01T00:00:00Z"}`,
+			calls:      1,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Campaign ends before start (422)",
+			body:       `{"name": "Wrong", "kind": "FIXED", "value": 100, "starts_at": "2024-04-01T00:00:00Z", "ends_at": "2024-03-01T00:00:00Z"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Unknown campaign kind (422)",
+			body:       `{"name": "Wrong", "kind": "CASHBACK", "value": 100, "starts_at": "2024-03-01T00:00:00Z", "ends_at": "2024-04-01T00:00:00Z"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Broken JSON (400)",
+			body:       `{"name": `,
+			calls:      0,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoCamp := mocks.NewMockCampaignRepo(ctrl)
+			campSrv := services.NewCampaignService(repoCamp)
+
+			_ = repoCamp.EXPECT().
+				AddCampaign(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				Return(nil)
+
+			// add chi context
+			rctx := chi.NewRouteContext()
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/campaigns", strings.NewReader(tt.body))
+
+			// add admin to context
+			ctxAdmin := context.WithValue(req.Context(), entities.CtxAdminKey{}, "admin")
+			req = req.WithContext(context.WithValue(ctxAdmin, chi.RouteCtxKey, rctx))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			campHand := NewHandlerCampaign(conf, campSrv)
+			campHand.AddCampaign(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err := res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestPauseCampaign(t *testing.T) {
+	tests := []struct {
+		name       string
+		campaignID string
+		pauseError error
+		statusCode int
+	}{
+		{
+			name:       "Pause campaign",
+			campaignID: uuid.Must(uuid.NewV4()).String(),
+			pauseError: nil,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Pause not existed campaign",
+			campaignID: uuid.Must(uuid.NewV4()).String(),
+			pauseError: entities.ErrCampaignNotFound,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoCamp := mocks.NewMockCampaignRepo(ctrl)
+			campSrv := services.NewCampaignService(repoCamp)
+
+			_ = repoCamp.EXPECT().
+				SetCampaignPaused(gomock.Any(), uuid.FromStringOrNil(tt.campaignID), true).
+				Times(1).
+				Return(tt.pauseError)
+
+			// add chi context with campaign id
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("campaignID", tt.campaignID)
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/campaigns/"+tt.campaignID+"/pause", nil)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			campHand := NewHandlerCampaign(conf, campSrv)
+			campHand.PauseCampaign(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err := res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// User's claims for orders uploaded by other users and admin's resolution.
+type HandlerDispute struct {
+	dispSrv *services.DisputeService
+	conf    *config.Config
+}
+
+func NewHandlerDispute(conf *config.Config, dispSrv *services.DisputeService) *HandlerDispute {
+	return &HandlerDispute{dispSrv: dispSrv, conf: conf}
+}
+
+// Open dispute for order of other user with evidence.
+func (u *HandlerDispute) AddDispute(res http.ResponseWriter, req *http.Request) {
+	// Get UserID from cxt values.
+	ctxConfig, ok := req.Context().Value(entities.MiddlwDTO{}).(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	var dr entities.DisputeRequest
+	if err := json.NewDecoder(req.Body).Decode(&dr); err != nil {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dispute := entities.NewDispute(userID, &dr)
+	if !dispute.IsValid() {
+		// 422
+		errt := "Dispute not valid: order number and evidence are required."
+		zap.S().Debugln(errt, dr)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	err := u.dispSrv.AddDispute(req.Context(), dispute)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrOrderNotFound):
+			// 404
+			http.Error(res, "Order not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrNotDisputable):
+			// 422
+			http.Error(res, "Order can't be disputed.", http.StatusUnprocessableEntity)
+		case errors.Is(err, entities.ErrDisputeExists):
+			// 409
+			http.Error(res, "Order alredy disputed.", http.StatusConflict)
+		default:
+			// 500
+			errt := "Error during dispute creation."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Dispute ", dispute.DisputeID, " opened for order ", dispute.OrderNr, " by user ", userID)
+	writeJSON(res, dispute, http.StatusCreated)
+}
+
+// User's disputes.
+func (u *HandlerDispute) GetDisputes(res http.ResponseWriter, req *http.Request) {
+	// Get UserID from cxt values.
+	ctxConfig, ok := req.Context().Value(entities.MiddlwDTO{}).(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	disputes, err := u.dispSrv.GetUserDisputes(req.Context(), ctxConfig.GetUserID())
+	if err != nil {
+		// 500
+		errt := "Cat't get disputes."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(disputes) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, disputes, http.StatusOK)
+}
+
+// Admin's list of disputes, filter by status (?status=OPEN).
+func (u *HandlerDispute) GetAllDisputes(res http.ResponseWriter, req *http.Request) {
+	status := entities.DisputeStatus(req.URL.Query().Get("status"))
+	disputes, err := u.dispSrv.GetDisputes(req.Context(), status)
+	if err != nil {
+		// 500
+		errt := "Cat't get disputes."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(disputes) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, disputes, http.StatusOK)
+}
+
+func (u *HandlerDispute) Reassign(res http.ResponseWriter, req *http.Request) {
+	u.decide(res, req, true)
+}
+
+func (u *HandlerDispute) Reject(res http.ResponseWriter, req *http.Request) {
+	u.decide(res, req, false)
+}
+
+func (u *HandlerDispute) decide(res http.ResponseWriter, req *http.Request, reassign bool) {
+	admin, ok := req.Context().Value(entities.CtxAdminKey{}).(string)
+	if !ok {
+		errt := "Cat't get admin from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	disputeID, err := uuid.FromString(chi.URLParam(req, "disputeID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Dispute not found.", http.StatusNotFound)
+		return
+	}
+
+	// Decision note is optional.
+	var dd entities.DisputeDecision
+	if err := json.NewDecoder(req.Body).Decode(&dd); err != nil && !errors.Is(err, io.EOF) {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var dispute *entities.Dispute
+	if reassign {
+		dispute, err = u.dispSrv.Reassign(req.Context(), disputeID, admin, dd.Note)
+	} else {
+		dispute, err = u.dispSrv.Reject(req.Context(), disputeID, admin, dd.Note)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrDisputeNotFound):
+			// 404
+			http.Error(res, "Dispute not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrDisputeDecided):
+			// 409
+			http.Error(res, "Dispute alredy decided.", http.StatusConflict)
+		default:
+			// 500
+			errt := "Error during dispute decision."
+			zap.S().Errorln(errt, disputeID, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Dispute ", disputeID, " ", dispute.Status, " by ", admin)
+	writeJSON(res, dispute, http.StatusOK)
+}
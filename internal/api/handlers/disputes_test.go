@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDispute(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		addError   error
+		statusCode int
+	}{
+		{
+			name:       "Dispute opened",
+			body:       `{"number": "12345678903", "evidence": "Receipt 0042 from 2024-04-20"}`,
+			calls:      1,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Order not found (404)",
+			body:       `{"number": "12345678903", "evidence": "Receipt"}`,
+			calls:      1,
+			addError:   entities.ErrOrderNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Own order (422)",
+			body:       `{"number": "12345678903", "evidence": "Receipt"}`,
+			calls:      1,
+			addError:   entities.ErrNotDisputable,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Order already disputed (409)",
+			body:       `{"number": "12345678903", "evidence": "Receipt"}`,
+			calls:      1,
+			addError:   entities.ErrDisputeExists,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Without evidence (422)",
+			body:       `{"number": "12345678903"}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Broken JSON (400)",
+			body:       `{"number": `,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoDisp := mocks.NewMockDisputeRepo(ctrl)
+			dispSrv := services.NewDisputeService(repoDisp, nil)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoDisp.EXPECT().
+				AddDispute(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, dispute *entities.Dispute) error {
+					assert.Equal(t, userID, dispute.Claimant)
+					assert.Equal(t, entities.DisputeOpen, dispute.Status)
+					return tt.addError
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/disputes", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			dispHand := NewHandlerDispute(conf, dispSrv)
+			dispHand.AddDispute(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestReassignDispute(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		moved       decimal.Decimal
+		decideError error
+		events      int
+		statusCode  int
+	}{
+		{
+			name:       "Reassign with accrual",
+			body:       `{"note": "Receipt checked"}`,
+			moved:      decimal.NewFromFloat(250),
+			events:     1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Reassign not processed order without note",
+			moved:      decimal.Zero,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "Reassign decided dispute (409)",
+			decideError: entities.ErrDisputeDecided,
+			statusCode:  http.StatusConflict,
+		},
+		{
+			name:        "Reassign not existed dispute (404)",
+			decideError: entities.ErrDisputeNotFound,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoDisp := mocks.NewMockDisputeRepo(ctrl)
+			bus := services.NewEventBus(10)
+			dispSrv := services.NewDisputeService(repoDisp, bus)
+
+			disputeID := uuid.Must(uuid.NewV4())
+			owner := uuid.Must(uuid.NewV4())
+			claimant := uuid.Must(uuid.NewV4())
+
+			var dispute *entities.Dispute
+			if tt.decideError == nil {
+				dispute = &entities.Dispute{DisputeID: disputeID, OrderNr: "12345678903", Owner: owner, Claimant: claimant, Status: entities.DisputeReassigned, Moved: tt.moved}
+			}
+
+			_ = repoDisp.EXPECT().
+				DecideDispute(gomock.Any(), disputeID, "admin", true, gomock.Any()).
+				Times(1).
+				Return(dispute, tt.decideError)
+
+			// balance events of claimant
+			events, _, cancel := bus.Subscribe(claimant, 0)
+			defer cancel()
+
+			// add chi context with dispute id
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("disputeID", disputeID.String())
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/disputes/"+disputeID.String()+"/reassign", strings.NewReader(tt.body))
+
+			// add admin to context
+			ctxAdmin := context.WithValue(req.Context(), entities.CtxAdminKey{}, "admin")
+			req = req.WithContext(context.WithValue(ctxAdmin, chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			dispHand := NewHandlerDispute(conf, dispSrv)
+			dispHand.Reassign(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err := res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			require.Len(t, events, tt.events)
+			if tt.events != 0 {
+				event := <-events
+				assert.Equal(t, entities.EventBalance, event.Kind)
+				assert.Contains(t, string(event.Data), `"amount":250`)
+			}
+		})
+	}
+}
+
+func TestDisputedAccrual(t *testing.T) {
+	tests := []struct {
+		name string
+		// Order's state at the end of polling.
+		updateError error
+		reassigned  bool
+		calls       int
+	}{
+		{
+			name:        "Disputed while waiting accrual answer",
+			updateError: entities.ErrOrderDisputed,
+		},
+		{
+			name:       "Reassigned while waiting accrual answer",
+			reassigned: true,
+			calls:      1,
+		},
+		{
+			name:  "Not disputed",
+			calls: 1,
+		},
+	}
+
+	app.InitLog()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoAcc := mocks.NewMockAccrualRepo(ctrl)
+			client := mocks.NewMockAccrualClient(ctrl)
+			repoTier := mocks.NewMockTierRepo(ctrl)
+			repoCamp := mocks.NewMockCampaignRepo(ctrl)
+			repoRef := mocks.NewMockReferralRepo(ctrl)
+			tierSrv := services.NewTierService(repoTier, nil, time.Hour)
+			campSrv := services.NewCampaignService(repoCamp)
+			refSrv := services.NewReferralService(repoRef, decimal.Zero, decimal.Zero, 0)
+			accSrv := services.NewAccrualService(repoAcc, client, tierSrv, campSrv, refSrv, 0, nil)
+
+			ownerID, err := uuid.NewV7()
+			require.NoError(t, err)
+			claimantID, err := uuid.NewV7()
+			require.NoError(t, err)
+			orderNr := "12345678903"
+
+			// current owner is read in status update
+			current := ownerID
+			if tt.reassigned {
+				current = claimantID
+			}
+
+			_ = repoAcc.EXPECT().
+				LoadPocessing(gomock.Any()).
+				Return([]entities.Order{{UserID: ownerID, OrderNr: orderNr, Status: entities.Status(entities.PROCESSING)}}, nil)
+			_ = repoAcc.EXPECT().
+				UpdateStatus(gomock.Any(), orderNr, entities.Status(entities.PROCESSING), gomock.Any()).
+				Return(ownerID, nil)
+			_ = client.EXPECT().
+				GetOrderStatus(orderNr).
+				Return(&entities.AccrualResponce{Order: orderNr, Status: string(entities.PROCESSED), Accrual: 500}, nil)
+			_ = repoAcc.EXPECT().
+				UpdateStatus(gomock.Any(), orderNr, entities.Status(entities.PROCESSED), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ entities.Status, accrual decimal.Decimal) (uuid.UUID, error) {
+					assert.True(t, accrual.Equal(decimal.NewFromInt(500)))
+					return current, tt.updateError
+				})
+
+			// accrual is credited to current owner only
+			_ = repoAcc.EXPECT().
+				AddBonuses(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, entry *entities.LedgerEntry) error {
+					assert.Equal(t, current, entry.UserID)
+					return nil
+				})
+			_ = repoTier.EXPECT().
+				GetLifetimeAccruals(gomock.Any(), current, gomock.Any()).
+				AnyTimes().
+				Return(decimal.Zero, errors.New("no tiers"))
+			_ = repoCamp.EXPECT().
+				GetActiveCampaigns(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				Return(nil, nil)
+			_ = repoRef.EXPECT().
+				RewardReferral(gomock.Any(), current, gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				Return(nil, nil)
+
+			accSrv.FetchAccrual(context.Background())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Keep connection alive through proxies.
+const heartbeat = 15 * time.Second
+
+// Stream of user's events over Server-Sent Events.
+type HandlerEvents struct {
+	bus  *services.EventBus
+	conf *config.Config
+}
+
+func NewHandlerEvents(conf *config.Config, bus *services.EventBus) *HandlerEvents {
+	return &HandlerEvents{bus: bus, conf: conf}
+}
+
+func (u *HandlerEvents) Stream(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		errt := "Streaming unsupported."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Resume after reconnect.
+	var lastID uint64
+	if last := req.Header.Get("Last-Event-ID"); last != "" {
+		id, err := strconv.ParseUint(last, 10, 64)
+		if err != nil {
+			// 400
+			http.Error(res, "Wrong Last-Event-ID.", http.StatusBadRequest)
+			return
+		}
+		lastID = id
+	}
+
+	events, missed, cancel := u.bus.Subscribe(userID, lastID)
+	defer cancel()
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	for _, event := range missed {
+		if err := writeEvent(res, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case event := <-events:
+			if err := writeEvent(res, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				zap.S().Debugln("Can't write heartbeat to event stream", err)
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(res http.ResponseWriter, event entities.Event) error {
+	_, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Kind, event.Data)
+	if err != nil {
+		zap.S().Debugln("Can't write to event stream", err)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		lastID    string
+		published int
+		expected  []string
+	}{
+		{
+			name:      "New events",
+			lastID:    "",
+			published: 2,
+			expected:  []string{"id: 5", "id: 7"},
+		},
+		{
+			name:      "Resume after reconnect",
+			lastID:    "1",
+			published: 1,
+			expected:  []string{"id: 2", "id: 5"},
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			bus := services.NewEventBus(10)
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+			otherID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			// events before connection: user's 1 and 2, other user's 3
+			bus.Publish(userID, entities.EventOrderStatus, entities.OrderEvent{OrderNr: "12345678903", Status: entities.PROCESSING})
+			bus.Publish(userID, entities.EventBalance, entities.BalanceEvent{Kind: entities.ACCRUAL, Amount: 100})
+			bus.Publish(otherID, entities.EventBalance, entities.BalanceEvent{Kind: entities.ACCRUAL, Amount: 100})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+				NewHandlerEvents(conf, bus).Stream(res, req)
+			}))
+			defer srv.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/user/events", nil)
+			require.NoError(t, err)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+			// events after connection, skip other user's events
+			go func() {
+				time.Sleep(time.Millisecond * 50)
+				for i := 0; i < tt.published; i++ {
+					bus.Publish(otherID, entities.EventBalance, entities.BalanceEvent{Kind: entities.ACCRUAL, Amount: 1})
+					bus.Publish(userID, entities.EventOrderStatus, entities.OrderEvent{OrderNr: "12345678903", Status: entities.PROCESSED})
+				}
+			}()
+
+			ids := make([]string, 0)
+			scanner := bufio.NewScanner(res.Body)
+			for len(ids) < len(tt.expected) && scanner.Scan() {
+				if strings.HasPrefix(scanner.Text(), "id: ") {
+					ids = append(ids, scanner.Text())
+				}
+			}
+			// only user's events, other user's events are between them
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
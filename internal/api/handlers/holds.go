@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+type HandlerHold struct {
+	holdSrv  *services.HoldService
+	calcSrv  *services.CalculationService
+	conf     *config.Config
+	orderSrv *services.OrderService
+}
+
+func NewHandlerHold(conf *config.Config, holdSrv *services.HoldService, calc *services.CalculationService, orders *services.OrderService) *HandlerHold {
+	return &HandlerHold{holdSrv: holdSrv, calcSrv: calc, conf: conf, orderSrv: orders}
+}
+
+// Reserve user's bonuses.
+func (u *HandlerHold) CreateHold(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	var hr entities.HoldRequest
+	if err := json.NewDecoder(req.Body).Decode(&hr); err != nil {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amount := decimal.NewFromFloat(hr.Sum)
+	if !amount.IsPositive() {
+		// 422
+		errt := "Hold sum must be positive."
+		zap.S().Debugln(errt, hr.Sum)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Captured hold is a withdrawal, withdrawal limits are checked on reservation.
+	if !writePolicyError(res, u.calcSrv.CheckPolicy(req.Context(), userID, amount, decimal.NewFromFloat(hr.OrderSum))) {
+		return
+	}
+
+	hold, err := u.holdSrv.CreateHold(req.Context(), userID, amount, u.calcSrv.Limits())
+	if err != nil {
+		var policyErr *entities.PolicyError
+		switch {
+		case errors.Is(err, entities.ErrNotEnoughBonuses):
+			// 402
+			http.Error(res, "Not enuogh bonuses.", http.StatusPaymentRequired)
+		case errors.As(err, &policyErr):
+			// 422
+			writePolicyError(res, policyErr)
+		default:
+			// 500
+			errt := "Error during hold creation."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(res, hold, http.StatusCreated)
+}
+
+func (u *HandlerHold) GetHolds(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	holds, err := u.holdSrv.GetHolds(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Cat't get holds."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(holds) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	jsonHolds, err := json.Marshal(holds)
+	if err != nil {
+		errt := "Error during Marshal user's holds"
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// set content type
+	res.Header().Add("Content-Type", "application/json")
+
+	// set status code 200
+	res.WriteHeader(http.StatusOK)
+
+	_, err = res.Write(jsonHolds)
+	if err != nil {
+		zap.S().Errorln("Can't write to response in GetHolds handler", err)
+	}
+}
+
+// Turn hold to the withdrawal against order number.
+func (u *HandlerHold) CaptureHold(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	holdID, err := uuid.FromString(chi.URLParam(req, "holdID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Hold not found.", http.StatusNotFound)
+		return
+	}
+
+	var cr entities.CaptureRequest
+	if err := json.NewDecoder(req.Body).Decode(&cr); err != nil {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !writeOrderError(res, u.orderSrv.ValidateOrder(cr.OrderNr)) {
+		return
+	}
+
+	existed, err := u.orderSrv.IsExist(req.Context(), cr.OrderNr)
+	if err != nil {
+		// 500
+		errt := "Error during capture. Checking order duplication error."
+		zap.S().Errorln(errt, cr.OrderNr, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+	if existed {
+		// 422
+		errt := "Order alredy existed."
+		zap.S().Debugln(errt, cr.OrderNr)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	hold, err := u.holdSrv.CaptureHold(req.Context(), userID, holdID, cr.OrderNr)
+	if err != nil {
+		var pgErr *pq.Error
+		switch {
+		case errors.Is(err, entities.ErrHoldNotFound):
+			// 404
+			http.Error(res, "Hold not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrHoldNotActive):
+			// 409
+			http.Error(res, "Hold is expired or alredy closed.", http.StatusConflict)
+		case errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code:
+			// 422
+			http.Error(res, "Order alredy existed.", http.StatusUnprocessableEntity)
+		default:
+			// 500
+			errt := "Error during hold capture."
+			zap.S().Errorln(errt, holdID, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(res, hold, http.StatusOK)
+}
+
+// Release hold, return bonuses to user's balance.
+func (u *HandlerHold) VoidHold(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	holdID, err := uuid.FromString(chi.URLParam(req, "holdID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Hold not found.", http.StatusNotFound)
+		return
+	}
+
+	hold, err := u.holdSrv.VoidHold(req.Context(), userID, holdID)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrHoldNotFound):
+			// 404
+			http.Error(res, "Hold not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrHoldNotActive):
+			// 409
+			http.Error(res, "Hold is expired or alredy closed.", http.StatusConflict)
+		default:
+			// 500
+			errt := "Error during hold void."
+			zap.S().Errorln(errt, holdID, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(res, hold, http.StatusOK)
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHold(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestURL string
+		body       string
+		holdError  error
+		policyCode string
+		statusCode int
+	}{
+		{
+			name:       "Create hold success",
+			requestURL: "http://localhost:8080/api/user/balance/holds",
+			body:       `{"sum": 12.5}`,
+			holdError:  nil,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Create hold - 402 Payment Required",
+			requestURL: "http://localhost:8080/api/user/balance/holds",
+			body:       `{"sum": 1000}`,
+			holdError:  entities.ErrNotEnoughBonuses,
+			statusCode: http.StatusPaymentRequired,
+		},
+		{
+			name:       "Create hold - negative sum (422)",
+			requestURL: "http://localhost:8080/api/user/balance/holds",
+			body:       `{"sum": -1}`,
+			holdError:  nil,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Create hold - daily limit exceeded on reservation (422)",
+			requestURL: "http://localhost:8080/api/user/balance/holds",
+			body:       `{"sum": 50}`,
+			holdError:  entities.NewPolicyError(entities.DailyLimitExceeded, "daily withdrawals limit is 100"),
+			policyCode: entities.DailyLimitExceeded,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Create hold - above withdrawal limit (422)",
+			requestURL: "http://localhost:8080/api/user/balance/holds",
+			body:       `{"sum": 5000}`,
+			policyCode: entities.WithdrawAboveMax,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	conf.PassJWT = "JWTsecret"
+	conf.HoldTTL = time.Minute
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoHold := mocks.NewMockHoldRepo(ctrl)
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+
+			holdSrv := services.NewHoldService(repoHold, conf.HoldTTL, nil)
+			calcSrv := services.NewCalcService(mocks.NewMockCalcRepo(ctrl), entities.WithdrawPolicy{Max: decimal.NewFromInt(2000)}, nil)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoHold.EXPECT().
+				CreateHold(gomock.Any(), userID, gomock.Any(), gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, userID uuid.UUID, amount decimal.Decimal, expires time.Time, _ entities.WithdrawLimits) (*entities.Hold, error) {
+					if tt.holdError != nil {
+						return nil, tt.holdError
+					}
+					return &entities.Hold{HoldID: uuid.Must(uuid.NewV4()), UserID: userID, Amount: amount, Status: entities.HELD, Created: time.Now(), Expires: expires}, nil
+				})
+
+			// add chi context
+			rctx := chi.NewRouteContext()
+			req := httptest.NewRequest(http.MethodPost, tt.requestURL, strings.NewReader(tt.body))
+
+			// add User and isRegister true tu context
+			ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			holdHand := NewHandlerHold(conf, holdSrv, calcSrv, orderSrv)
+			holdHand.CreateHold(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			t.Log(string(b))
+
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if res.StatusCode == http.StatusCreated {
+				var hold struct {
+					ID     string  `json:"id"`
+					Sum    float64 `json:"sum"`
+					Status string  `json:"status"`
+				}
+				err = json.Unmarshal(b, &hold)
+				require.NoError(t, err)
+				assert.Equal(t, 12.5, hold.Sum)
+				assert.Equal(t, string(entities.HELD), hold.Status)
+			}
+
+			if tt.policyCode != "" {
+				var policyErr entities.PolicyError
+				err = json.Unmarshal(b, &policyErr)
+				require.NoError(t, err)
+				assert.Equal(t, tt.policyCode, policyErr.Code)
+			}
+		})
+	}
+}
+
+func TestVoidHold(t *testing.T) {
+	tests := []struct {
+		name       string
+		holdID     string
+		holdError  error
+		statusCode int
+	}{
+		{
+			name:       "Void hold success",
+			holdID:     uuid.Must(uuid.NewV4()).String(),
+			holdError:  nil,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Void hold not found",
+			holdID:     uuid.Must(uuid.NewV4()).String(),
+			holdError:  entities.ErrHoldNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Void expired hold",
+			holdID:     uuid.Must(uuid.NewV4()).String(),
+			holdError:  entities.ErrHoldNotActive,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Void hold with wrong id",
+			holdID:     "not-uuid",
+			holdError:  nil,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	conf.PassJWT = "JWTsecret"
+	conf.HoldTTL = time.Minute
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoHold := mocks.NewMockHoldRepo(ctrl)
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+
+			holdSrv := services.NewHoldService(repoHold, conf.HoldTTL, nil)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoHold.EXPECT().
+				VoidHold(gomock.Any(), userID, gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, userID uuid.UUID, holdID uuid.UUID) (*entities.Hold, error) {
+					if tt.holdError != nil {
+						return nil, tt.holdError
+					}
+					return &entities.Hold{HoldID: holdID, UserID: userID, Amount: decimal.NewFromInt(10), Status: entities.VOIDED}, nil
+				})
+
+			// add chi context with hold id
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("holdID", tt.holdID)
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/balance/holds/"+tt.holdID+"/void", nil)
+
+			// add User and isRegister true tu context
+			ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			holdHand := NewHandlerHold(conf, holdSrv, nil, orderSrv)
+			holdHand.VoidHold(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
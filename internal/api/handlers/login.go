@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/shulganew/gophermart/internal/app/config"
 	"github.com/shulganew/gophermart/internal/entities"
 	"github.com/shulganew/gophermart/internal/services"
@@ -11,12 +14,14 @@ import (
 )
 
 type HandlerLogin struct {
-	usrSrt *services.UserService
-	conf   *config.Config
+	usrSrt  *services.UserService
+	sessSrv *services.SessionService
+	guard   *services.LoginGuard
+	conf    *config.Config
 }
 
-func NewHandlerLogin(conf *config.Config, userServ *services.UserService) *HandlerLogin {
-	return &HandlerLogin{usrSrt: userServ, conf: conf}
+func NewHandlerLogin(conf *config.Config, userServ *services.UserService, sessSrv *services.SessionService, guard *services.LoginGuard) *HandlerLogin {
+	return &HandlerLogin{usrSrt: userServ, sessSrv: sessSrv, guard: guard, conf: conf}
 }
 
 func (h *HandlerLogin) LoginUser(res http.ResponseWriter, req *http.Request) {
@@ -28,26 +33,62 @@ func (h *HandlerLogin) LoginUser(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// Same answer for existing and unknown logins.
+	ip := clientIP(req)
+	wait, err := h.guard.Attempt(req.Context(), user.Login, ip)
+	if !writeThrottled(res, "Too many login attempts, try later.", wait, err) {
+		return
+	}
+
 	userID, isValid := h.usrSrt.IsValid(req.Context(), user.Login, user.Password)
 	if !isValid {
+		// Failed attempt is already counted by guard.
+		zap.S().Infoln("Failed login attempt from: ", ip)
 		// Wrond user login or password 401
 		http.Error(res, "Wrong login or password", http.StatusUnauthorized)
 		return
 	}
 
+	if err := h.guard.Success(req.Context(), user.Login, ip); err != nil {
+		zap.S().Errorln("Can't reset login attempts: ", err)
+	}
+
 	user.UUID = *userID
 
 	zap.S().Debug("Login sucsess, user id is: ", userID)
-	jwt, _ := services.BuildJWTString(*userID, h.conf.PassJWT)
+	writeSession(res, req, h.sessSrv, *userID)
+}
 
-	res.Header().Add("Content-Type", "text/plain")
-	res.Header().Add("Authorization", jwt)
+// Remove lockout of login, admin only.
+func (h *HandlerLogin) Unlock(res http.ResponseWriter, req *http.Request) {
+	admin, _ := req.Context().Value(entities.CtxAdminKey{}).(string)
 
-	// set status code 200
-	res.WriteHeader(http.StatusOK)
+	login := chi.URLParam(req, "login")
+	err := h.guard.Unlock(req.Context(), admin, login)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrNotLocked):
+			// 404
+			http.Error(res, "Login has no failed attempts.", http.StatusNotFound)
+		default:
+			// 500
+			errt := "Error during login unlock."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Login unlocked by admin: ", login, admin)
+	// 204
+	res.WriteHeader(http.StatusNoContent)
+}
 
-	_, err := res.Write([]byte("User loged in."))
+// Client address without port, proxy headers are not trusted.
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
 	if err != nil {
-		zap.S().Errorln("Can't write to response in LoginUser  handler", err)
+		return req.RemoteAddr
 	}
+	return host
 }
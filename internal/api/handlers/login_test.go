@@ -3,12 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gofrs/uuid"
@@ -19,6 +21,7 @@ import (
 	"github.com/shulganew/gophermart/internal/services"
 	"github.com/shulganew/gophermart/internal/services/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -65,7 +68,41 @@ func TestUserlogin(t *testing.T) {
 			defer ctrl.Finish()
 
 			repoUser := mocks.NewMockUserRepo(ctrl)
-			userServ := services.NewUserService(repoUser)
+			userServ := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), time.Minute, time.Hour)
+
+			// session is started only after successful login
+			_ = repoSess.EXPECT().
+				AddSession(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(nil)
+
+			repoLogin := mocks.NewMockLoginRepo(ctrl)
+			guard := services.NewLoginGuard(repoLogin, entities.LoginPolicy{Delay: time.Second, LoginLimit: 5, IPLimit: 50, Lockout: time.Minute})
+
+			// attempt is counted for login and IP before password check
+			_ = repoLogin.EXPECT().
+				TakeAttempt(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, keys []entities.AttemptKey, _ entities.LoginPolicy) (time.Duration, error) {
+					assert.Equal(t, []entities.AttemptKey{{Scope: entities.AttemptLogin, Key: tt.login, Limit: 5}, {Scope: entities.AttemptIP, Key: "192.0.2.1", Limit: 50}}, keys)
+					return 0, nil
+				})
+
+			// success resets login's failures and doesn't count attempt of IP
+			resets := 1
+			if tt.statusCode == http.StatusUnauthorized {
+				resets = 0
+			}
+			_ = repoLogin.EXPECT().
+				ResetAttempts(gomock.Any(), entities.AttemptLogin, tt.login).
+				Times(resets).
+				Return(nil)
+			_ = repoLogin.EXPECT().
+				RefundAttempt(gomock.Any(), entities.AttemptIP, "192.0.2.1").
+				Times(resets).
+				Return(nil)
 
 			uuid, err := uuid.NewV7()
 			assert.NoError(t, err)
@@ -102,7 +139,7 @@ func TestUserlogin(t *testing.T) {
 			resRecord := httptest.NewRecorder()
 
 			// Make request.
-			userLogin := NewHandlerLogin(conf, userServ)
+			userLogin := NewHandlerLogin(conf, userServ, sessSrv, guard)
 			userLogin.LoginUser(resRecord, req)
 
 			// Get result.
@@ -119,6 +156,179 @@ func TestUserlogin(t *testing.T) {
 			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
 
 			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.statusCode == http.StatusOK {
+				var tokens entities.TokenPair
+				err = json.Unmarshal(b, &tokens)
+				assert.NoError(t, err)
+				assert.Equal(t, tokens.AccessToken, res.Header.Get("Authorization"))
+				assert.NotEmpty(t, tokens.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestLoginThrottle(t *testing.T) {
+	tests := []struct {
+		name       string
+		login      *entities.LoginAttempts
+		ip         *entities.LoginAttempts
+		noMaxDelay bool
+		statusCode int
+		retry      string
+	}{
+		{
+			name:       "Progressive delay after failures (429)",
+			login:      &entities.LoginAttempts{Failures: 3, LastFailure: ptrTime(time.Now().Add(-time.Second))},
+			ip:         &entities.LoginAttempts{},
+			statusCode: http.StatusTooManyRequests,
+			retry:      "3",
+		},
+		{
+			name:       "Login locked (429)",
+			login:      &entities.LoginAttempts{LockedUntil: ptrTime(time.Now().Add(time.Minute))},
+			ip:         &entities.LoginAttempts{},
+			statusCode: http.StatusTooManyRequests,
+			retry:      "60",
+		},
+		{
+			name:       "IP locked (429)",
+			login:      &entities.LoginAttempts{},
+			ip:         &entities.LoginAttempts{LockedUntil: ptrTime(time.Now().Add(time.Minute))},
+			statusCode: http.StatusTooManyRequests,
+			retry:      "60",
+		},
+		{
+			name:       "Many failures without max delay, delay doesn't overflow (429)",
+			login:      &entities.LoginAttempts{Failures: 100, LastFailure: ptrTime(time.Now().Add(-time.Second))},
+			ip:         &entities.LoginAttempts{},
+			noMaxDelay: true,
+			statusCode: http.StatusTooManyRequests,
+			retry:      "4611686018",
+		},
+		{
+			name:       "Delay passed, wrong password (401)",
+			login:      &entities.LoginAttempts{Failures: 1, LastFailure: ptrTime(time.Now().Add(-time.Minute))},
+			ip:         &entities.LoginAttempts{},
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{PassJWT: "JWTsecret"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// login not found, answer is the same as for wrong password
+			repoUser := mocks.NewMockUserRepo(ctrl)
+			userServ := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			_ = repoUser.EXPECT().
+				GetByLogin(gomock.Any(), "user").
+				AnyTimes().
+				Return(nil, errors.New("not found"))
+
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), time.Minute, time.Hour)
+
+			repoLogin := mocks.NewMockLoginRepo(ctrl)
+			policy := entities.LoginPolicy{Delay: time.Second, MaxDelay: time.Minute, LoginLimit: 5, IPLimit: 50, Lockout: time.Minute}
+			if tt.noMaxDelay {
+				policy.MaxDelay = 0
+			}
+			guard := services.NewLoginGuard(repoLogin, policy)
+
+			// storage checks locked attempts of login and IP with policy
+			_ = repoLogin.EXPECT().
+				TakeAttempt(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, keys []entities.AttemptKey, policy entities.LoginPolicy) (time.Duration, error) {
+					require.Len(t, keys, 2)
+					assert.Equal(t, entities.AttemptLogin, keys[0].Scope)
+					assert.Equal(t, entities.AttemptIP, keys[1].Scope)
+					return policy.Wait([]entities.LoginAttempts{*tt.login, *tt.ip}, time.Now()), nil
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/login", strings.NewReader(`{"login": "user", "password": "qwerty"}`))
+			req.RemoteAddr = "192.0.2.1:41234"
+			req.Header.Add("Content-Type", "application/json")
+
+			// Create status recorder.
+			resRecord := httptest.NewRecorder()
+
+			userLogin := NewHandlerLogin(conf, userServ, sessSrv, guard)
+			userLogin.LoginUser(resRecord, req)
+
+			// Get result.
+			res := resRecord.Result()
+			err := res.Body.Close()
+			assert.NoError(t, err)
+
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			assert.Equal(t, tt.retry, res.Header.Get("Retry-After"))
+		})
+	}
+}
+
+func TestUnlockLogin(t *testing.T) {
+	tests := []struct {
+		name        string
+		unlockError error
+		statusCode  int
+	}{
+		{
+			name:       "Login unlocked",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:        "Login without failures (404)",
+			unlockError: entities.ErrNotLocked,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoLogin := mocks.NewMockLoginRepo(ctrl)
+			guard := services.NewLoginGuard(repoLogin, entities.LoginPolicy{})
+
+			_ = repoLogin.EXPECT().
+				UnlockLogin(gomock.Any(), "alice", "user").
+				Return(tt.unlockError)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("login", "user")
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/logins/user/unlock", nil)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(context.WithValue(ctx, entities.CtxAdminKey{}, "alice"))
+
+			// Create status recorder.
+			resRecord := httptest.NewRecorder()
+
+			userLogin := NewHandlerLogin(conf, nil, nil, guard)
+			userLogin.Unlock(resRecord, req)
+
+			// Get result.
+			res := resRecord.Result()
+			err := res.Body.Close()
+			assert.NoError(t, err)
+
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
 		})
 	}
 }
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Orders of merchants' servers with API keys and admin API for the keys.
+type HandlerMerchant struct {
+	merchSrv *services.MerchantService
+	orders   *HandlerOrder
+	conf     *config.Config
+}
+
+func NewHandlerMerchant(conf *config.Config, merchSrv *services.MerchantService, orders *HandlerOrder) *HandlerMerchant {
+	return &HandlerMerchant{merchSrv: merchSrv, orders: orders, conf: conf}
+}
+
+// Upload order for user with login, order is attributed to merchant's key.
+func (u *HandlerMerchant) AddOrder(res http.ResponseWriter, req *http.Request) {
+	key, ok := req.Context().Value(entities.CtxMerchantKey{}).(*entities.MerchantKey)
+	if !ok {
+		http.Error(res, "API key not found.", http.StatusUnauthorized)
+		return
+	}
+
+	var mr entities.MerchantOrderRequest
+	if err := json.NewDecoder(req.Body).Decode(&mr); err != nil {
+		// 400
+		http.Error(res, "Can't decode order request.", http.StatusBadRequest)
+		return
+	}
+	if mr.Login == "" {
+		// 400
+		http.Error(res, "Login not found in request.", http.StatusBadRequest)
+		return
+	}
+	if mr.Purchase != nil && !mr.Purchase.IsValid() {
+		// 422
+		errt := "Purchase metadata not valid."
+		zap.S().Debugln(errt, mr.OrderNr)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	user, ok := u.findUser(res, req, mr.Login)
+	if !ok {
+		return
+	}
+
+	zap.S().Infoln("Set Order for user: ", user.UserID, " Order: ", mr.OrderNr, " by ", key.Actor())
+	order := entities.NewOrder(user.UserID, mr.OrderNr, false, decimal.Zero, decimal.Zero)
+	order.Purchase = mr.Purchase
+	order.MerchantKey = &key.KeyID
+
+	u.orders.uploadOrder(res, req, order)
+}
+
+// Order of user with login from query (?login=), only orders uploaded by the key are found.
+func (u *HandlerMerchant) GetOrder(res http.ResponseWriter, req *http.Request) {
+	key, ok := req.Context().Value(entities.CtxMerchantKey{}).(*entities.MerchantKey)
+	if !ok {
+		http.Error(res, "API key not found.", http.StatusUnauthorized)
+		return
+	}
+
+	login := req.URL.Query().Get("login")
+	if login == "" {
+		// 400
+		http.Error(res, "Login not found in query.", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := u.findUser(res, req, login)
+	if !ok {
+		return
+	}
+
+	orderNr := chi.URLParam(req, "number")
+	order, err := u.merchSrv.GetOrder(req.Context(), key, user.UserID, orderNr)
+	if err != nil {
+		if errors.Is(err, entities.ErrOrderNotFound) {
+			// 404
+			http.Error(res, "Order not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Cat't get order."
+		zap.S().Errorln(errt, orderNr, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(res, order, http.StatusOK)
+}
+
+// Issue new key, the key is shown only in this answer.
+func (u *HandlerMerchant) AddKey(res http.ResponseWriter, req *http.Request) {
+	admin, _ := req.Context().Value(entities.CtxAdminKey{}).(string)
+
+	var kr entities.MerchantKeyRequest
+	if err := json.NewDecoder(req.Body).Decode(&kr); err != nil {
+		// 400
+		http.Error(res, "Can't decode JSON", http.StatusBadRequest)
+		return
+	}
+
+	key := entities.NewMerchantKey(admin, &kr)
+	if !key.IsValid() {
+		// 422
+		errt := "Merchant key not valid."
+		zap.S().Debugln(errt, kr)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	issued, err := u.merchSrv.Issue(req.Context(), key)
+	if err != nil {
+		// 500
+		errt := "Error during merchant key creation."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	zap.S().Infoln("Merchant key ", key.Prefix, " for ", key.Merchant, " created by ", admin)
+	writeJSON(res, issued, http.StatusCreated)
+}
+
+func (u *HandlerMerchant) GetKeys(res http.ResponseWriter, req *http.Request) {
+	keys, err := u.merchSrv.GetKeys(req.Context())
+	if err != nil {
+		// 500
+		errt := "Cat't get merchant keys."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(keys) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, keys, http.StatusOK)
+}
+
+func (u *HandlerMerchant) RevokeKey(res http.ResponseWriter, req *http.Request) {
+	admin, _ := req.Context().Value(entities.CtxAdminKey{}).(string)
+
+	keyID, err := uuid.FromString(chi.URLParam(req, "keyID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Merchant key not found.", http.StatusNotFound)
+		return
+	}
+
+	err = u.merchSrv.Revoke(req.Context(), admin, keyID)
+	if err != nil {
+		if errors.Is(err, entities.ErrKeyNotFound) {
+			// 404
+			http.Error(res, "Merchant key not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Cat't revoke merchant key."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	zap.S().Infoln("Merchant key ", keyID, " revoked by ", admin)
+	res.WriteHeader(http.StatusNoContent)
+}
+
+func (u *HandlerMerchant) findUser(res http.ResponseWriter, req *http.Request, login string) (*entities.UserInfo, bool) {
+	user, err := u.merchSrv.FindUser(req.Context(), login)
+	if err != nil {
+		if errors.Is(err, entities.ErrUserNotFound) {
+			// 404
+			http.Error(res, "User not found.", http.StatusNotFound)
+			return nil, false
+		}
+		// 500
+		errt := "Cat't get user."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return nil, false
+	}
+	return user, true
+}
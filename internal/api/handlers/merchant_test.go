@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/api/middlewares"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerchantOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		scopes     []string
+		body       string
+		userError  error
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Order uploaded by merchant",
+			apiKey:     "gm_a2V5",
+			scopes:     []string{entities.ScopeOrdersWrite},
+			body:       `{"login": "User", "number": "12345678903"}`,
+			calls:      1,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Key without scope (403)",
+			apiKey:     "gm_a2V5",
+			scopes:     []string{entities.ScopeOrdersRead},
+			body:       `{"login": "User", "number": "12345678903"}`,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Unknown login (404)",
+			apiKey:     "gm_a2V5",
+			scopes:     []string{entities.ScopeOrdersWrite},
+			body:       `{"login": "nobody", "number": "12345678903"}`,
+			userError:  entities.ErrUserNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Without login (400)",
+			apiKey:     "gm_a2V5",
+			scopes:     []string{entities.ScopeOrdersWrite},
+			body:       `{"number": "12345678903"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Revoked or expired key (401)",
+			apiKey:     "gm_b2xk",
+			body:       `{"login": "User", "number": "12345678903"}`,
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "Without key (401)",
+			body:       `{"login": "User", "number": "12345678903"}`,
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	sum := sha256.Sum256([]byte("gm_a2V5"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoMerch := mocks.NewMockMerchantRepo(ctrl)
+			merchSrv := services.NewMerchantService(repoMerch)
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+			keyID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			// only active keys are found by hash
+			_ = repoMerch.EXPECT().
+				UseMerchantKey(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, hash string) (*entities.MerchantKey, error) {
+					if hash != hex.EncodeToString(sum[:]) {
+						return nil, entities.ErrKeyNotFound
+					}
+					return &entities.MerchantKey{KeyID: keyID, Merchant: "shop", Scopes: tt.scopes}, nil
+				})
+			_ = repoMerch.EXPECT().
+				FindUser(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(&entities.UserInfo{UserID: userID, Login: "user"}, tt.userError)
+
+			// order is attributed to merchant's key
+			_ = repoOrder.EXPECT().
+				AddOrder(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, data *entities.AddOrder) (*entities.BatchOrder, error) {
+					assert.Equal(t, userID.String(), data.UserID)
+					require.NotNil(t, data.MerchantKey)
+					assert.Equal(t, keyID, *data.MerchantKey)
+					return &entities.BatchOrder{OrderNr: data.OrderNr, Inserted: true, Owner: &userID}, nil
+				})
+
+			merchant := NewHandlerMerchant(conf, merchSrv, NewHandlerOrder(conf, nil, nil, orderSrv))
+			r := chi.NewRouter()
+			r.Use(middlewares.APIKey(merchSrv))
+			r.With(middlewares.RequireScope(entities.ScopeOrdersWrite)).Post("/api/merchant/orders", merchant.AddOrder)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/merchant/orders", strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", "application/json")
+			if tt.apiKey != "" {
+				req.Header.Add(middlewares.APIKeyHeader, tt.apiKey)
+			}
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			r.ServeHTTP(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestMerchantGetOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		query      string
+		uploaded   bool
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Order uploaded by the key",
+			scopes:     []string{entities.ScopeOrdersRead},
+			query:      "?login=User",
+			uploaded:   true,
+			calls:      1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Order not uploaded by the key (404)",
+			scopes:     []string{entities.ScopeOrdersRead},
+			query:      "?login=User",
+			calls:      1,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Key without scope (403)",
+			scopes:     []string{entities.ScopeOrdersWrite},
+			query:      "?login=User",
+			uploaded:   true,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Without login (400)",
+			scopes:     []string{entities.ScopeOrdersRead},
+			uploaded:   true,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	sum := sha256.Sum256([]byte("gm_a2V5"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoMerch := mocks.NewMockMerchantRepo(ctrl)
+			merchSrv := services.NewMerchantService(repoMerch)
+
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+			keyID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			_ = repoMerch.EXPECT().
+				UseMerchantKey(gomock.Any(), hex.EncodeToString(sum[:])).
+				AnyTimes().
+				Return(&entities.MerchantKey{KeyID: keyID, Merchant: "shop", Scopes: tt.scopes}, nil)
+			_ = repoMerch.EXPECT().
+				FindUser(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(&entities.UserInfo{UserID: userID, Login: "user"}, nil)
+
+			// order is looked up with the key of request
+			_ = repoMerch.EXPECT().
+				GetMerchantOrder(gomock.Any(), gomock.Any(), userID, "12345678903").
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, key *entities.MerchantKey, _ uuid.UUID, order string) (*entities.Order, error) {
+					assert.Equal(t, keyID, key.KeyID)
+					if !tt.uploaded {
+						return nil, entities.ErrOrderNotFound
+					}
+					return &entities.Order{UserID: userID, OrderNr: order, Status: entities.NEW}, nil
+				})
+			_ = repoMerch.EXPECT().
+				GetOrderHistory(gomock.Any(), userID, "12345678903").
+				AnyTimes().
+				Return([]entities.StatusChange{}, nil)
+
+			merchant := NewHandlerMerchant(conf, merchSrv, nil)
+			r := chi.NewRouter()
+			r.Use(middlewares.APIKey(merchSrv))
+			r.With(middlewares.RequireScope(entities.ScopeOrdersRead)).Get("/api/merchant/orders/{number}", merchant.GetOrder)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/merchant/orders/12345678903"+tt.query, nil)
+			req.Header.Add(middlewares.APIKeyHeader, "gm_a2V5")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			r.ServeHTTP(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestAddMerchantKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Key issued",
+			body:       `{"merchant": "shop", "scopes": ["orders:write", "orders:read"]}`,
+			calls:      1,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Unknown scope (422)",
+			body:       `{"merchant": "shop", "scopes": ["orders:delete"]}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Expired key (422)",
+			body:       `{"merchant": "shop", "scopes": ["orders:write"], "expires_at": "2020-01-01T00:00:00Z"}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Broken JSON (400)",
+			body:       `{"merchant": `,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoMerch := mocks.NewMockMerchantRepo(ctrl)
+			merchSrv := services.NewMerchantService(repoMerch)
+
+			keyID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			// only hash of issued key is stored
+			var stored string
+			_ = repoMerch.EXPECT().
+				AddMerchantKey(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, key *entities.MerchantKey) error {
+					assert.Equal(t, "admin", key.CreatedBy)
+					key.KeyID = keyID
+					stored = key.KeyHash
+					return nil
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/admin/merchant-keys", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), entities.CtxAdminKey{}, "admin"))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			merchant := NewHandlerMerchant(conf, merchSrv, nil)
+			merchant.AddKey(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if tt.statusCode == http.StatusCreated {
+				var issued struct {
+					Key         string         `json:"key"`
+					MerchantKey map[string]any `json:"merchant_key"`
+				}
+				err = json.NewDecoder(res.Body).Decode(&issued)
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(issued.Key, entities.MerchantKeyPrefix))
+				sum := sha256.Sum256([]byte(issued.Key))
+				assert.Equal(t, hex.EncodeToString(sum[:]), stored)
+				assert.Equal(t, keyID.String(), issued.MerchantKey["id"])
+				assert.Equal(t, issued.Key[:len(issued.MerchantKey["prefix"].(string))], issued.MerchantKey["prefix"])
+				assert.NotContains(t, issued.MerchantKey, "key_hash")
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/shulganew/gophermart/internal/app/config"
 	"github.com/shulganew/gophermart/internal/entities"
@@ -49,40 +53,59 @@ func (u *HandlerOrder) AddOrder(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// JSON variant of upload with purchase metadata.
+	var purchase *entities.Purchase
 	orderNr := string(body)
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		var or entities.OrderRequest
+		if err := json.Unmarshal(body, &or); err != nil {
+			// 400
+			http.Error(res, "Can't decode order request.", http.StatusBadRequest)
+			return
+		}
+		if !or.Purchase.IsValid() {
+			// 422
+			errt := "Purchase metadata not valid."
+			zap.S().Debugln(errt, or.OrderNr)
+			http.Error(res, errt, http.StatusUnprocessableEntity)
+			return
+		}
+		orderNr = or.OrderNr
+		purchase = &or.Purchase
+	}
+
 	zap.S().Infoln("Set Order for user: ", userID, " Order: ", orderNr)
 	// Create order
 	order := entities.NewOrder(userID, orderNr, false, decimal.Zero, decimal.Zero)
+	order.Purchase = purchase
 
-	isValid := order.IsValid()
-	if !isValid {
-		// 422
-		errt := "Order nuber not vaild."
-		zap.S().Debugln(errt, orderNr)
-		http.Error(res, errt, http.StatusUnprocessableEntity)
+	u.uploadOrder(res, req, order)
+}
+
+// Validate and save uploaded order, answer depends on order's owner.
+func (u *HandlerOrder) uploadOrder(res http.ResponseWriter, req *http.Request, order *entities.Order) {
+	orderNr := order.OrderNr
+	if !writeOrderError(res, u.orderSrv.ValidateOrder(orderNr)) {
 		return
 	}
 
-	isExist, err := u.orderSrv.IsExist(req.Context(), order.OrderNr)
+	outcome, err := u.orderSrv.AddOrder(req.Context(), false, order)
 	if err != nil {
 		// 500
-		errt := "Get error during checkig if order existed."
+		errt := "Get error during save new order."
 		zap.S().Error(errt, orderNr, err)
 		http.Error(res, errt, http.StatusInternalServerError)
 		return
 	}
 
-	if !isExist {
-		err = u.orderSrv.AddOrder(req.Context(), false, order)
-		if err != nil {
-			// 500
-			errt := "Get error during save new order."
-			zap.S().Error(errt, orderNr, err)
-			http.Error(res, errt, http.StatusInternalServerError)
-			return
+	switch outcome {
+	case entities.UploadAccepted:
+		zap.S().Infoln("New order added: ", order.OrderNr)
+		if u.conf.RegisterOrders {
+			u.accSrv.RegisterOrder(order)
 		}
 
-		zap.S().Infoln("New order added: ", order.OrderNr)
 		// 202 - New order
 		res.WriteHeader(http.StatusAccepted)
 
@@ -91,37 +114,15 @@ func (u *HandlerOrder) AddOrder(res http.ResponseWriter, req *http.Request) {
 			zap.S().Errorln("Can't write to response in AddOrder  handler", err)
 		}
 		return
-	}
-
-	// Order existed for other user.
-	isExistOther, err := u.orderSrv.IsExistForOtherUser(req.Context(), userID, orderNr)
-	if err != nil {
-		errt := "Get error during search duplicated order for other user."
-		zap.S().Error(errt, orderNr)
-		http.Error(res, errt, http.StatusInternalServerError)
-		return
-	}
-	if isExistOther {
+	case entities.UploadOtherUser:
 		// 409
 		errt := "Order duplicated for Other User."
 		zap.S().Debugln(errt, orderNr)
 		http.Error(res, errt, http.StatusConflict)
 		return
-	}
-
-	// Order Existed for this user.
-	// Check if order created befower with withdraw as prepaid.
-	isPreorder, err := u.calcSrv.IsPreOrder(req.Context(), userID, orderNr)
-	if err != nil {
-		errt := "Get error during preorder search."
-		zap.S().Error(errt, err, orderNr)
-		http.Error(res, errt, http.StatusInternalServerError)
-		return
-	}
-
-	if isPreorder {
+	case entities.UploadPreOrder:
 		// Move prepaid preoreder to regular order.
-		err = u.calcSrv.MovePreOrder(req.Context(), order)
+		err := u.calcSrv.MovePreOrder(req.Context(), order)
 		if err != nil {
 			errt := "Get error during preorder update."
 			zap.S().Debugln(errt, orderNr)
@@ -156,10 +157,34 @@ func (u *HandlerOrder) GetOrders(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	userID := ctxConfig.GetUserID()
+	u.writeOrders(res, req, ctxConfig.GetUserID())
+}
+
+// Orders of any user, admin only.
+func (u *HandlerOrder) GetUserOrders(res http.ResponseWriter, req *http.Request) {
+	userID, err := uuid.FromString(chi.URLParam(req, "userID"))
+	if err != nil {
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
+		return
+	}
+
+	u.writeOrders(res, req, userID)
+}
+
+// Write page of user's orders with filter from query.
+func (u *HandlerOrder) writeOrders(res http.ResponseWriter, req *http.Request, userID uuid.UUID) {
+	filter, err := entities.ParseOrderFilter(req.URL.Query())
+	if err != nil {
+		// 400
+		errt := "Wrong orders filter."
+		zap.S().Debugln(errt, req.URL.RawQuery)
+		http.Error(res, errt, http.StatusBadRequest)
+		return
+	}
 
 	// Load user's orders
-	orders, err := u.orderSrv.GetOrders(req.Context(), userID)
+	orders, next, err := u.orderSrv.GetOrders(req.Context(), userID, filter)
 
 	zap.S().Infoln("GetOrders len", len(orders), "for user: ", userID)
 	for _, ord := range orders {
@@ -192,6 +217,17 @@ func (u *HandlerOrder) GetOrders(res http.ResponseWriter, req *http.Request) {
 
 	zap.S().Infoln("Get Orders: ", string(jsonOrders))
 
+	// Link to the next page
+	if next != nil {
+		cursor := next.Encode()
+		nextURL := *req.URL
+		query := nextURL.Query()
+		query.Set("cursor", cursor)
+		nextURL.RawQuery = query.Encode()
+		res.Header().Add("Link", "<"+nextURL.RequestURI()+">; rel=\"next\"")
+		res.Header().Add("X-Next-Cursor", cursor)
+	}
+
 	// set content type
 	res.Header().Add("Content-Type", "application/json")
 
@@ -203,3 +239,86 @@ func (u *HandlerOrder) GetOrders(res http.ResponseWriter, req *http.Request) {
 		zap.S().Errorln("Can't write to response in GetOrders  handler", err)
 	}
 }
+
+// Order's current state and status history.
+func (u *HandlerOrder) GetOrder(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	u.writeOrder(res, req, ctxConfig.GetUserID(), chi.URLParam(req, "number"))
+}
+
+func (u *HandlerOrder) writeOrder(res http.ResponseWriter, req *http.Request, userID uuid.UUID, orderNr string) {
+	order, err := u.orderSrv.GetOrder(req.Context(), userID, orderNr)
+	if err != nil {
+		if errors.Is(err, entities.ErrOrderNotFound) {
+			// 404
+			http.Error(res, "Order not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Cat't get order."
+		zap.S().Errorln(errt, orderNr, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(res, order, http.StatusOK)
+}
+
+// Withdraw upload of order, allowed while order is new.
+func (u *HandlerOrder) DeleteOrder(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+	orderNr := chi.URLParam(req, "number")
+
+	err := u.orderSrv.DeleteOrder(req.Context(), userID, orderNr)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrOrderNotFound):
+			// 404
+			http.Error(res, "Order not found.", http.StatusNotFound)
+		case errors.Is(err, entities.ErrOrderNotDeletable):
+			// 409
+			http.Error(res, "Only new orders can be deleted.", http.StatusConflict)
+		default:
+			// 500
+			errt := "Cat't delete order."
+			zap.S().Errorln(errt, orderNr, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Order deleted: ", orderNr, " by user ", userID)
+	// 204
+	res.WriteHeader(http.StatusNoContent)
+}
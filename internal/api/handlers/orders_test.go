@@ -6,7 +6,9 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ShiraazMoollatjie/goluhn"
 	"github.com/go-chi/chi/v5"
@@ -73,16 +75,19 @@ func TestOrders(t *testing.T) {
 			repoOrder := mocks.NewMockOrderRepo(ctrl)
 			repoAcc := mocks.NewMockAccrualRepo(ctrl)
 
-			userSrv := services.NewUserService(repoUser)
-			calcSrv := services.NewCalcService(repoCalc)
-			orderSrv := services.NewOrderService(repoOrder)
+			userSrv := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			calcSrv := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
 			client := client.NewAccrualClient(conf)
 
-			accSrv := services.NewAccrualService(repoAcc, client)
+			tierSrv := services.NewTierService(mocks.NewMockTierRepo(ctrl), conf.Tiers, conf.TierWindow)
+			campSrv := services.NewCampaignService(mocks.NewMockCampaignRepo(ctrl))
+			refSrv := services.NewReferralService(mocks.NewMockReferralRepo(ctrl), decimal.Zero, decimal.Zero, 0)
+			accSrv := services.NewAccrualService(repoAcc, client, tierSrv, campSrv, refSrv, conf.ReturnWindow, nil)
 
-			uuid, err := uuid.NewV7()
+			id, err := uuid.NewV7()
 			assert.NoError(t, err)
-			user := entities.User{UUID: uuid, Login: "Test123", Password: "123"}
+			user := entities.User{UUID: id, Login: "Test123", Password: "123"}
 
 			_ = repoUser.EXPECT().
 				AddUser(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -90,7 +95,7 @@ func TestOrders(t *testing.T) {
 				Return(&user.UUID, nil)
 
 			_ = repoOrder.EXPECT().
-				GetOrders(gomock.Any(), gomock.Any()).
+				GetOrders(gomock.Any(), gomock.Any(), gomock.Any()).
 				Times(1).
 				Return(tt.orders, nil)
 
@@ -107,7 +112,7 @@ func TestOrders(t *testing.T) {
 
 			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
 
-			jwt, _ := services.BuildJWTString(*userID, conf.PassJWT)
+			jwt, _ := services.BuildJWTString(*userID, uuid.Nil, entities.RoleUser, entities.NewHMACKeys(conf.PassJWT), time.Hour)
 
 			req.Header.Add("Authorization", jwt)
 			req.Header.Add("Content-Type", "text/plain")
@@ -155,3 +160,708 @@ func getOrders() []entities.Order {
 	return []entities.Order{*entities.NewOrder(userID, goluhn.Generate(10), false, decimal.NewFromFloat(20), decimal.NewFromFloat(200)),
 		*entities.NewOrder(userID, goluhn.Generate(10), false, decimal.NewFromFloat(5), decimal.NewFromFloat(100))}
 }
+
+func TestOrdersPage(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		calls      int
+		limit      int
+		stored     int
+		status     []string
+		nextPage   bool
+		statusCode int
+	}{
+		{
+			name:       "First page with next",
+			query:      "limit=2",
+			calls:      1,
+			limit:      2,
+			stored:     3,
+			nextPage:   true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Last page",
+			query:      "limit=2&cursor=" + entities.OrderCursor{Uploaded: time.Now(), OrderNr: "12345678903"}.Encode(),
+			calls:      1,
+			limit:      2,
+			stored:     1,
+			nextPage:   false,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Without limit and cursor all orders",
+			calls:      1,
+			stored:     entities.DefaultPageLimit + 50,
+			nextPage:   false,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Cursor without limit, default page",
+			query:      "cursor=" + entities.OrderCursor{Uploaded: time.Now(), OrderNr: "12345678903"}.Encode(),
+			calls:      1,
+			limit:      entities.DefaultPageLimit,
+			stored:     entities.DefaultPageLimit + 1,
+			nextPage:   true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Filter by status and dates",
+			query:      "status=processed,invalid&from=2024-03-01T00:00:00Z&to=2024-04-01T00:00:00Z&sort=asc",
+			calls:      1,
+			stored:     2,
+			status:     []string{"PROCESSED", "INVALID"},
+			nextPage:   false,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Wrong status (400)",
+			query:      "status=DONE",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Limit too big (400)",
+			query:      "limit=100000",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Broken cursor (400)",
+			query:      "cursor=broken",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			orders := make([]entities.Order, 0, tt.stored)
+			for i := 0; i < tt.stored; i++ {
+				orders = append(orders, *entities.NewOrder(userID, goluhn.Generate(10), false, decimal.Zero, decimal.NewFromFloat(10)))
+			}
+
+			_ = repoOrder.EXPECT().
+				GetOrders(gomock.Any(), userID, gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, _ uuid.UUID, filter *entities.OrderFilter) ([]entities.Order, error) {
+					// service asks one more order to find next page, without limit all orders
+					if tt.limit == 0 {
+						assert.Equal(t, 0, filter.Limit)
+					} else {
+						assert.Equal(t, tt.limit+1, filter.Limit)
+					}
+					assert.Equal(t, tt.status, filter.Status)
+					return orders, nil
+				})
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/user/orders?"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, nil, nil, orderSrv)
+			ordersHand.GetOrders(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var responses []entities.OrderResponse
+			err = json.NewDecoder(res.Body).Decode(&responses)
+			require.NoError(t, err)
+			if tt.limit == 0 {
+				assert.Len(t, responses, tt.stored)
+			} else {
+				assert.LessOrEqual(t, len(responses), tt.limit)
+			}
+
+			cursor := res.Header.Get("X-Next-Cursor")
+			assert.Equal(t, tt.nextPage, cursor != "")
+			if tt.nextPage {
+				assert.Contains(t, res.Header.Get("Link"), `rel="next"`)
+				next, err := entities.ParseCursor(cursor)
+				require.NoError(t, err)
+				assert.Equal(t, responses[len(responses)-1].OrderNr, next.OrderNr)
+			}
+		})
+	}
+}
+
+func TestOrderDetail(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderNr    string
+		getError   error
+		history    int
+		statusCode int
+	}{
+		{
+			name:       "Processed order with history",
+			orderNr:    goluhn.Generate(10),
+			history:    3,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Order of other user (404)",
+			orderNr:    goluhn.Generate(10),
+			getError:   entities.ErrOrderNotFound,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			var order *entities.Order
+			if tt.getError == nil {
+				order = entities.NewOrder(userID, tt.orderNr, false, decimal.Zero, decimal.NewFromFloat(500))
+				order.Status = entities.PROCESSED
+			}
+
+			_ = repoOrder.EXPECT().
+				GetOrder(gomock.Any(), userID, tt.orderNr).
+				Times(1).
+				Return(order, tt.getError)
+
+			accrual := decimal.NewFromFloat(500)
+			history := []entities.StatusChange{
+				{Status: entities.NEW, Source: entities.SourceUpload, Created: time.Now().Add(-time.Minute)},
+				{Status: entities.PROCESSING, Source: entities.SourceAccrual, Created: time.Now().Add(-time.Second * 30)},
+				{Status: entities.PROCESSED, Accrual: &accrual, Source: entities.SourceAccrual, Created: time.Now()},
+			}
+			calls := 0
+			if tt.getError == nil {
+				calls = 1
+			}
+			_ = repoOrder.EXPECT().
+				GetOrderHistory(gomock.Any(), gomock.Any(), tt.orderNr).
+				Times(calls).
+				Return(history, nil)
+
+			// add chi context with order number
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("number", tt.orderNr)
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/user/orders/"+tt.orderNr, nil)
+			ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, nil, nil, orderSrv)
+			ordersHand.GetOrder(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var detail struct {
+				Number  string  `json:"number"`
+				Status  string  `json:"status"`
+				Accrual float64 `json:"accrual"`
+				History []struct {
+					Status  string  `json:"status"`
+					Accrual float64 `json:"accrual"`
+					Source  string  `json:"source"`
+				} `json:"history"`
+			}
+			err = json.NewDecoder(res.Body).Decode(&detail)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.orderNr, detail.Number)
+			assert.Equal(t, "PROCESSED", detail.Status)
+			require.Len(t, detail.History, tt.history)
+			assert.Equal(t, "NEW", detail.History[0].Status)
+			assert.Equal(t, 500.0, detail.History[2].Accrual)
+		})
+	}
+}
+
+func TestAddOrderPurchase(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Order with purchase metadata",
+			body:       `{"number":"12345678903","store_id":"store-1","amount":1500.5,"currency":"RUB","purchased_at":"2024-04-20T10:00:00Z","goods":[{"description":"Чайник Bork","price":1500.5}]}`,
+			calls:      1,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Wrong currency (422)",
+			body:       `{"number":"12345678903","amount":10,"currency":"rubles"}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Wrong order number (422)",
+			body:       `{"number":"12345678900","amount":10,"currency":"RUB"}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Broken JSON (400)",
+			body:       `{"number":`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoOrder.EXPECT().
+				AddOrder(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, data *entities.AddOrder) (*entities.BatchOrder, error) {
+					require.NotNil(t, data.Purchase)
+					assert.Equal(t, "store-1", data.Purchase.StoreID)
+					assert.Equal(t, "RUB", data.Purchase.Currency)
+					assert.Len(t, data.Purchase.Goods, 1)
+					return &entities.BatchOrder{OrderNr: data.OrderNr, Inserted: true, Owner: &userID}, nil
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/orders", strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", "application/json; charset=utf-8")
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, nil, nil, orderSrv)
+			ordersHand.AddOrder(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestAddOrderOutcome(t *testing.T) {
+	tests := []struct {
+		name       string
+		inserted   bool
+		otherUser  bool
+		preOrder   bool
+		statusCode int
+	}{
+		{
+			name:       "New order (202)",
+			inserted:   true,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Already uploaded by user (200)",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "User's preorder moved (200)",
+			preOrder:   true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Uploaded by other user (409)",
+			otherUser:  true,
+			statusCode: http.StatusConflict,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			repoCalc := mocks.NewMockCalcRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+			calcSrv := services.NewCalcService(repoCalc, entities.WithdrawPolicy{}, nil)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+			owner := userID
+			if tt.otherUser {
+				owner, err = uuid.NewV7()
+				assert.NoError(t, err)
+			}
+			orderNr := goluhn.Generate(10)
+
+			// one statement for insert and owner check
+			_ = repoOrder.EXPECT().
+				AddOrder(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(&entities.BatchOrder{OrderNr: orderNr, Inserted: tt.inserted, Owner: &owner, IsPreOrder: tt.preOrder}, nil)
+
+			moves := 0
+			if tt.preOrder {
+				moves = 1
+			}
+			_ = repoCalc.EXPECT().
+				MovePreOrder(gomock.Any(), gomock.Any()).
+				Times(moves).
+				Return(nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/orders", strings.NewReader(orderNr))
+			req.Header.Add("Content-Type", "text/plain")
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, calcSrv, nil, orderSrv)
+			ordersHand.AddOrder(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestOrderRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      string
+		orderNr    string
+		code       string
+		statusCode int
+	}{
+		{
+			name:       "Luhn number by default rule",
+			rules:      ":0:luhn,460:13:gs1,77:6:mod11",
+			orderNr:    "12345678903",
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "GS1 number of store",
+			rules:      ":0:luhn,460:13:gs1,77:6:mod11",
+			orderNr:    "4601234567893",
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Mod 11 number of store",
+			rules:      ":0:luhn,460:13:gs1,77:6:mod11",
+			orderNr:    "771236",
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Wrong GS1 check digit (422)",
+			rules:      ":0:luhn,460:13:gs1,77:6:mod11",
+			orderNr:    "4601234567890",
+			code:       entities.OrderCheckDigit,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Wrong length (422)",
+			rules:      ":0:luhn,460:13:gs1,77:6:mod11",
+			orderNr:    "460123",
+			code:       entities.OrderWrongLength,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Not digits (422)",
+			rules:      ":0:luhn",
+			orderNr:    "12ab",
+			code:       entities.OrderNotDigits,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Unknown store (422)",
+			rules:      "460:13:gs1",
+			orderNr:    "12345678903",
+			code:       entities.OrderUnknownStore,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rules, err := config.ParseOrderRules(tt.rules)
+			require.NoError(t, err)
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.NewOrderValidator(rules))
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			calls := 0
+			if tt.statusCode == http.StatusAccepted {
+				calls = 1
+			}
+			_ = repoOrder.EXPECT().
+				AddOrder(gomock.Any(), gomock.Any()).
+				Times(calls).
+				Return(&entities.BatchOrder{OrderNr: tt.orderNr, Inserted: true, Owner: &userID}, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/orders", strings.NewReader(tt.orderNr))
+			req.Header.Add("Content-Type", "text/plain")
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, nil, nil, orderSrv)
+			ordersHand.AddOrder(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.code != "" {
+				var orderErr entities.OrderError
+				err = json.NewDecoder(res.Body).Decode(&orderErr)
+				require.NoError(t, err)
+				assert.Equal(t, tt.code, orderErr.Code)
+			}
+		})
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		orderNr     string
+		deleteError error
+		statusCode  int
+	}{
+		{
+			name:       "Delete new order",
+			orderNr:    "12345678903",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:        "Delete processing order (409)",
+			orderNr:     "12345678903",
+			deleteError: entities.ErrOrderNotDeletable,
+			statusCode:  http.StatusConflict,
+		},
+		{
+			name:        "Delete order of other user (404)",
+			orderNr:     "12345678903",
+			deleteError: entities.ErrOrderNotFound,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoOrder.EXPECT().
+				DeleteOrder(gomock.Any(), userID, tt.orderNr).
+				Times(1).
+				Return(tt.deleteError)
+
+			// add chi context with order number
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("number", tt.orderNr)
+			req := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/user/orders/"+tt.orderNr, nil)
+			ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			ordersHand := NewHandlerOrder(conf, nil, nil, orderSrv)
+			ordersHand.DeleteOrder(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestDeleteThenAccrual(t *testing.T) {
+	tests := []struct {
+		name string
+		// Order is deleted before status is set to PROCESSING.
+		deletedBefore bool
+		accrualCalls  int
+	}{
+		{
+			name:         "Deleted while waiting accrual answer",
+			accrualCalls: 1,
+		},
+		{
+			name:          "Deleted before processing",
+			deletedBefore: true,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoOrder := mocks.NewMockOrderRepo(ctrl)
+			orderSrv := services.NewOrderService(repoOrder, entities.OrderValidator{})
+			repoAcc := mocks.NewMockAccrualRepo(ctrl)
+			client := mocks.NewMockAccrualClient(ctrl)
+			accSrv := services.NewAccrualService(repoAcc, client, nil, nil, nil, 0, nil)
+			ordersHand := NewHandlerOrder(conf, nil, accSrv, orderSrv)
+
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+			orderNr := "12345678903"
+
+			// user deletes the order
+			deleted := false
+			deleteOrder := func() {
+				rctx := chi.NewRouteContext()
+				rctx.URLParams.Add("number", orderNr)
+				req := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/user/orders/"+orderNr, nil)
+				ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+				req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+
+				resRecord := httptest.NewRecorder()
+				ordersHand.DeleteOrder(resRecord, req)
+				res := resRecord.Result()
+				assert.NoError(t, res.Body.Close())
+				assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			}
+			_ = repoOrder.EXPECT().
+				DeleteOrder(gomock.Any(), userID, orderNr).
+				DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string) error {
+					deleted = true
+					return nil
+				})
+
+			// poller's snapshot is loaded before deletion
+			_ = repoAcc.EXPECT().
+				LoadPocessing(gomock.Any()).
+				DoAndReturn(func(_ context.Context) ([]entities.Order, error) {
+					orders := []entities.Order{{UserID: userID, OrderNr: orderNr, Status: entities.Status(entities.NEW)}}
+					if tt.deletedBefore {
+						deleteOrder()
+					}
+					return orders, nil
+				})
+			_ = repoAcc.EXPECT().
+				UpdateStatus(gomock.Any(), orderNr, gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, _ string, _ entities.Status, _ decimal.Decimal) (uuid.UUID, error) {
+					if deleted {
+						return uuid.Nil, entities.ErrOrderNotFound
+					}
+					return userID, nil
+				})
+			_ = client.EXPECT().
+				GetOrderStatus(orderNr).
+				Times(tt.accrualCalls).
+				DoAndReturn(func(orderNr string) (*entities.AccrualResponce, error) {
+					deleteOrder()
+					return &entities.AccrualResponce{Order: orderNr, Status: string(entities.PROCESSED), Accrual: 500}, nil
+				})
+			// deleted order is never credited
+			_ = repoAcc.EXPECT().
+				AddBonuses(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			accSrv.FetchAccrual(context.Background())
+			assert.True(t, deleted)
+		})
+	}
+}
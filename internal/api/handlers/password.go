@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Password change and reset.
+type HandlerPassword struct {
+	passSrv *services.PasswordService
+	guard   *services.LoginGuard
+	conf    *config.Config
+}
+
+func NewHandlerPassword(conf *config.Config, passSrv *services.PasswordService, guard *services.LoginGuard) *HandlerPassword {
+	return &HandlerPassword{passSrv: passSrv, guard: guard, conf: conf}
+}
+
+// Change password of logged in user, other sessions are logged out.
+func (u *HandlerPassword) ChangePassword(res http.ResponseWriter, req *http.Request) {
+	// Get UserID from cxt values.
+	ctxConfig, ok := req.Context().Value(entities.MiddlwDTO{}).(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	var change entities.PasswordChange
+	if err := json.NewDecoder(req.Body).Decode(&change); err != nil {
+		// 400
+		http.Error(res, "Can't decode JSON", http.StatusBadRequest)
+		return
+	}
+
+	err := u.passSrv.ChangePassword(req.Context(), ctxConfig.GetUserID(), ctxConfig.GetSessionID(), change.OldPassword, change.NewPassword)
+	if err != nil {
+		// 400
+		if writeValidationError(res, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, entities.ErrWrongPassword):
+			// 403
+			http.Error(res, "Wrong password.", http.StatusForbidden)
+		default:
+			// 500
+			errt := "Error during password change."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	zap.S().Infoln("Password changed by user: ", ctxConfig.GetUserID())
+	// 204
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// Send reset token, answer doesn't show if login exists.
+func (u *HandlerPassword) RequestReset(res http.ResponseWriter, req *http.Request) {
+	var reset entities.ResetRequest
+	if err := json.NewDecoder(req.Body).Decode(&reset); err != nil || reset.Login == "" {
+		// 400
+		http.Error(res, "Login not found in request.", http.StatusBadRequest)
+		return
+	}
+
+	// Requests are limited per login and per IP, as login attempts.
+	wait, err := u.guard.AttemptReset(req.Context(), reset.Login, clientIP(req))
+	if !writeThrottled(res, "Too many password reset requests, try later.", wait, err) {
+		return
+	}
+
+	u.passSrv.RequestReset(req.Context(), reset.Login)
+
+	// 202
+	res.WriteHeader(http.StatusAccepted)
+}
+
+// Set new password with reset token.
+func (u *HandlerPassword) ConfirmReset(res http.ResponseWriter, req *http.Request) {
+	var confirm entities.ResetConfirm
+	if err := json.NewDecoder(req.Body).Decode(&confirm); err != nil || confirm.Token == "" {
+		// 400
+		http.Error(res, "Reset token not found.", http.StatusBadRequest)
+		return
+	}
+
+	err := u.passSrv.ConfirmReset(req.Context(), confirm.Token, confirm.NewPassword)
+	if err != nil {
+		// 400
+		if writeValidationError(res, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, entities.ErrResetNotValid):
+			// 400
+			http.Error(res, "Reset token is not valid or expired.", http.StatusBadRequest)
+		default:
+			// 500
+			errt := "Error during password reset."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 204
+	res.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		registered bool
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Password changed",
+			body:       `{"old_password": "qwerty", "new_password": "asdfgh"}`,
+			registered: true,
+			calls:      1,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Wrong old password (403)",
+			body:       `{"old_password": "asdfgh", "new_password": "zxcvbn"}`,
+			registered: true,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Empty new password (400)",
+			body:       `{"old_password": "qwerty", "new_password": ""}`,
+			registered: true,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Not logged in (401)",
+			body:       `{"old_password": "qwerty", "new_password": "asdfgh"}`,
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	current, err := bcrypt.GenerateFromPassword([]byte("qwerty"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoPass := mocks.NewMockPasswordRepo(ctrl)
+			passSrv := services.NewPasswordService(repoPass, services.NewUserService(nil, entities.CredentialPolicy{}), mocks.NewMockNotifier(ctrl), time.Hour)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+			sessionID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoPass.EXPECT().
+				GetPasswordHash(gomock.Any(), userID).
+				AnyTimes().
+				Return(string(current), nil)
+
+			// current session is kept, other sessions are revoked
+			_ = repoPass.EXPECT().
+				SetPassword(gomock.Any(), userID, gomock.Any(), sessionID).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string, _ uuid.UUID) error {
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("asdfgh")))
+					return nil
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/password", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, tt.registered).WithSession(sessionID)))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			passHand := NewHandlerPassword(conf, passSrv, nil)
+			passHand.ChangePassword(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	tests := []struct {
+		name       string
+		login      string
+		userError  error
+		wait       time.Duration
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Reset token sent",
+			login:      "user",
+			calls:      1,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Too many requests (429)",
+			login:      "user",
+			wait:       time.Minute,
+			statusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:       "Unknown login, same answer",
+			login:      "nobody",
+			userError:  errors.New("not found"),
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Without login (400)",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoPass := mocks.NewMockPasswordRepo(ctrl)
+			notify := mocks.NewMockNotifier(ctrl)
+			passSrv := services.NewPasswordService(repoPass, services.NewUserService(nil, entities.CredentialPolicy{}), notify, time.Hour)
+			repoLogin := mocks.NewMockLoginRepo(ctrl)
+			guard := services.NewLoginGuard(repoLogin, entities.LoginPolicy{LoginLimit: 5, IPLimit: 50})
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			// every request is counted in reset scopes
+			_ = repoLogin.EXPECT().
+				TakeAttempt(gomock.Any(), gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, keys []entities.AttemptKey, _ entities.LoginPolicy) (time.Duration, error) {
+					assert.Equal(t, []entities.AttemptKey{{Scope: entities.AttemptReset, Key: tt.login, Limit: 5}, {Scope: entities.AttemptResetIP, Key: "192.0.2.1", Limit: 50}}, keys)
+					return tt.wait, nil
+				})
+
+			// token is sent in background after answer
+			done := make(chan struct{})
+			_ = repoPass.EXPECT().
+				GetByLogin(gomock.Any(), tt.login).
+				AnyTimes().
+				DoAndReturn(func(_ context.Context, _ string) (*entities.User, error) {
+					if tt.userError != nil {
+						close(done)
+					}
+					return &entities.User{UUID: userID, Login: tt.login}, tt.userError
+				})
+
+			// only hash of sent token is stored
+			var stored string
+			_ = repoPass.EXPECT().
+				AddPasswordReset(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, reset *entities.PasswordReset) error {
+					assert.Equal(t, userID, reset.UserID)
+					assert.WithinDuration(t, time.Now().Add(time.Hour), reset.Expires, time.Minute)
+					stored = reset.TokenHash
+					return nil
+				})
+			_ = notify.EXPECT().
+				SendPasswordReset(gomock.Any(), tt.login, gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, _ string, token string, _ time.Time) error {
+					sum := sha256.Sum256([]byte(token))
+					assert.Equal(t, hex.EncodeToString(sum[:]), stored)
+					close(done)
+					return nil
+				})
+
+			body := `{"login": "` + tt.login + `"}`
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/password/reset", strings.NewReader(body))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			passHand := NewHandlerPassword(conf, passSrv, guard)
+			passHand.RequestReset(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			if tt.statusCode == http.StatusTooManyRequests {
+				assert.Equal(t, "60", res.Header.Get("Retry-After"))
+			}
+
+			if tt.statusCode == http.StatusAccepted {
+				select {
+				case <-done:
+				case <-time.After(time.Second):
+					t.Error("reset is not processed")
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmReset(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		resetError error
+		statusCode int
+	}{
+		{
+			name:       "Password reset",
+			body:       `{"token": "cmVzZXQ", "new_password": "asdfgh"}`,
+			calls:      1,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Used or expired token (400)",
+			body:       `{"token": "cmVzZXQ", "new_password": "asdfgh"}`,
+			calls:      1,
+			resetError: entities.ErrResetNotValid,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Empty new password (400)",
+			body:       `{"token": "cmVzZXQ", "new_password": ""}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Without token (400)",
+			body:       `{"new_password": "asdfgh"}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	sum := sha256.Sum256([]byte("cmVzZXQ"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoPass := mocks.NewMockPasswordRepo(ctrl)
+			passSrv := services.NewPasswordService(repoPass, services.NewUserService(nil, entities.CredentialPolicy{}), mocks.NewMockNotifier(ctrl), time.Hour)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoPass.EXPECT().
+				ResetPassword(gomock.Any(), hex.EncodeToString(sum[:]), gomock.Any()).
+				Times(tt.calls).
+				Return(userID, tt.resetError)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/password/reset/confirm", strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			passHand := NewHandlerPassword(conf, passSrv, nil)
+			passHand.ConfirmReset(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
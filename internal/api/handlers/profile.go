@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+type HandlerProfile struct {
+	tierSrv *services.TierService
+	conf    *config.Config
+}
+
+func NewHandlerProfile(conf *config.Config, tierSrv *services.TierService) *HandlerProfile {
+	return &HandlerProfile{tierSrv: tierSrv, conf: conf}
+}
+
+// User's loyalty tier and progress to the next tier.
+func (u *HandlerProfile) GetProfile(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	profile, err := u.tierSrv.GetProfile(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Cat't get user's profile."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	jsonProfile, err := json.Marshal(profile)
+	if err != nil {
+		errt := "Error during Marshal user's profile"
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// set content type
+	res.Header().Add("Content-Type", "application/json")
+
+	// set status code 200
+	res.WriteHeader(http.StatusOK)
+
+	_, err = res.Write(jsonProfile)
+	if err != nil {
+		zap.S().Errorln("Can't write to response in GetProfile handler", err)
+	}
+}
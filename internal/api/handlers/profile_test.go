@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		lifetime   decimal.Decimal
+		storedTier string
+		tier       string
+		nextTier   string
+		toNextTier float64
+		changed    bool
+		statusCode int
+	}{
+		{
+			name:       "Bronze user without changes",
+			lifetime:   decimal.NewFromFloat(100),
+			storedTier: "bronze",
+			tier:       "bronze",
+			nextTier:   "silver",
+			toNextTier: 900,
+			changed:    false,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "User reached silver tier",
+			lifetime:   decimal.NewFromFloat(1200),
+			storedTier: "bronze",
+			tier:       "silver",
+			nextTier:   "gold",
+			toNextTier: 3800,
+			changed:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Gold user, no next tier",
+			lifetime:   decimal.NewFromFloat(7000),
+			storedTier: "gold",
+			tier:       "gold",
+			nextTier:   "",
+			toNextTier: 0,
+			changed:    false,
+			statusCode: http.StatusOK,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	conf.PassJWT = "JWTsecret"
+	conf.TierWindow = time.Hour * 24 * 90
+
+	tiers, err := config.ParseTiers("gold:5000:1.5,bronze:0:1,silver:1000:1.25")
+	require.NoError(t, err)
+	conf.Tiers = tiers
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoTier := mocks.NewMockTierRepo(ctrl)
+			tierSrv := services.NewTierService(repoTier, conf.Tiers, conf.TierWindow)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoTier.EXPECT().
+				GetLifetimeAccruals(gomock.Any(), userID, gomock.Any()).
+				Times(1).
+				Return(tt.lifetime, nil)
+
+			_ = repoTier.EXPECT().
+				GetTier(gomock.Any(), userID).
+				Times(1).
+				Return(tt.storedTier, nil)
+
+			changes := 0
+			if tt.changed {
+				changes = 1
+			}
+			_ = repoTier.EXPECT().
+				SetTier(gomock.Any(), gomock.Any()).
+				Times(changes).
+				Return(nil)
+
+			_ = repoTier.EXPECT().
+				GetTierHistory(gomock.Any(), userID).
+				Times(1).
+				Return([]entities.TierChange{}, nil)
+
+			// add chi context
+			rctx := chi.NewRouteContext()
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/user/profile", nil)
+
+			// add User and isRegister true tu context
+			ctxUser := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true))
+			req = req.WithContext(context.WithValue(ctxUser, chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			profileHand := NewHandlerProfile(conf, tierSrv)
+			profileHand.GetProfile(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+
+			var profile entities.Profile
+			err = json.NewDecoder(res.Body).Decode(&profile)
+			require.NoError(t, err)
+
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+			assert.Equal(t, tt.tier, profile.Tier)
+			assert.Equal(t, tt.nextTier, profile.NextTier)
+			assert.Equal(t, tt.toNextTier, profile.ToNextTier)
+		})
+	}
+}
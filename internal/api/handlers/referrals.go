@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+type HandlerReferral struct {
+	refSrv *services.ReferralService
+	conf   *config.Config
+}
+
+func NewHandlerReferral(conf *config.Config, refSrv *services.ReferralService) *HandlerReferral {
+	return &HandlerReferral{refSrv: refSrv, conf: conf}
+}
+
+// User's referral code, invited users and earned rewards.
+func (u *HandlerReferral) GetReferrals(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	info, err := u.refSrv.GetReferrals(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Cat't get referrals."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(res, info, http.StatusOK)
+}
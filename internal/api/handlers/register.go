@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 
 	"net/http"
 
+	"github.com/gofrs/uuid"
 	"github.com/shulganew/gophermart/internal/app/config"
 	"github.com/shulganew/gophermart/internal/entities"
 	"github.com/shulganew/gophermart/internal/services"
@@ -13,11 +15,13 @@ import (
 
 type HandlerRegister struct {
 	userSrv *services.UserService
+	refSrv  *services.ReferralService
+	sessSrv *services.SessionService
 	conf    *config.Config
 }
 
-func NewHandlerRegister(conf *config.Config, usrSrv *services.UserService) *HandlerRegister {
-	return &HandlerRegister{userSrv: usrSrv, conf: conf}
+func NewHandlerRegister(conf *config.Config, usrSrv *services.UserService, refSrv *services.ReferralService, sessSrv *services.SessionService) *HandlerRegister {
+	return &HandlerRegister{userSrv: usrSrv, refSrv: refSrv, sessSrv: sessSrv, conf: conf}
 }
 
 // Adding new user to Market.
@@ -33,8 +37,31 @@ func (u *HandlerRegister) SetUser(res http.ResponseWriter, req *http.Request) {
 	}
 	zap.S().Infoln("New user:", user.Login)
 
+	// Check referral code of inviting user.
+	var referrerID uuid.UUID
+	if user.Referrer != "" {
+		var err error
+		referrerID, err = u.refSrv.GetReferrer(req.Context(), user.Referrer)
+		if err != nil {
+			if errors.Is(err, entities.ErrReferralNotFound) {
+				// 400
+				http.Error(res, "Referral code not found.", http.StatusBadRequest)
+				return
+			}
+			// 500
+			errt := "Can't check referral code"
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+			return
+		}
+	}
+
 	userID, exist, err := u.userSrv.CreateUser(req.Context(), user.Login, user.Password)
 	if err != nil {
+		// Login or password rejected by policy 400
+		if writeValidationError(res, err) {
+			return
+		}
 		// If can't get UUID or hash pass 500
 		errt := "Can't get UUID or user hash"
 		zap.S().Errorln(errt, err)
@@ -51,17 +78,12 @@ func (u *HandlerRegister) SetUser(res http.ResponseWriter, req *http.Request) {
 
 	user.UUID = *userID
 
-	jwt, _ := services.BuildJWTString(user.UUID, u.conf.PassJWT)
-
-	res.Header().Add("Content-Type", "text/plain")
-
-	res.Header().Add("Authorization", jwt)
-
-	// set status code 200
-	res.WriteHeader(http.StatusOK)
-
-	_, err = res.Write([]byte("User added."))
-	if err != nil {
-		zap.S().Errorln("Can't write to response in SetUser handler", err)
+	if referrerID != uuid.Nil {
+		err = u.refSrv.AddReferral(req.Context(), referrerID, user.UUID)
+		if err != nil {
+			zap.S().Errorln("Can't add referral for user: ", user.Login, err)
+		}
 	}
+
+	writeSession(res, req, u.sessSrv, user.UUID)
 }
@@ -9,12 +9,14 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgerrcode"
 	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 	"github.com/shulganew/gophermart/internal/app"
 	"github.com/shulganew/gophermart/internal/app/config"
 	"github.com/shulganew/gophermart/internal/entities"
@@ -70,7 +72,14 @@ func TestUser(t *testing.T) {
 
 			// crete mock storege
 			repoUser := mocks.NewMockUserRepo(ctrl)
-			userSrv := services.NewUserService(repoUser)
+			userSrv := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), time.Minute, time.Hour)
+
+			_ = repoSess.EXPECT().
+				AddSession(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(nil)
 
 			uuid, err := uuid.NewV7()
 			assert.NoError(t, err)
@@ -107,7 +116,8 @@ func TestUser(t *testing.T) {
 			resRecord := httptest.NewRecorder()
 
 			// Make request
-			regUser := NewHandlerRegister(conf, userSrv)
+			refSrv := services.NewReferralService(mocks.NewMockReferralRepo(ctrl), decimal.Zero, decimal.Zero, 0)
+			regUser := NewHandlerRegister(conf, userSrv, refSrv, sessSrv)
 			regUser.SetUser(resRecord, req)
 
 			// get result
@@ -128,3 +138,227 @@ func TestUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserReferral(t *testing.T) {
+	tests := []struct {
+		name          string
+		referralCode  string
+		referralError error
+		addReferral   int
+		statusCode    int
+	}{
+		{
+			name:          "Registration with referral code",
+			referralCode:  "AB12CD34",
+			referralError: nil,
+			addReferral:   1,
+			statusCode:    http.StatusOK,
+		},
+		{
+			name:          "Registration with lower-case referral code",
+			referralCode:  "ab12cd34",
+			referralError: nil,
+			addReferral:   1,
+			statusCode:    http.StatusOK,
+		},
+		{
+			name:          "Registration with unknown referral code",
+			referralCode:  "XXXXXXXX",
+			referralError: entities.ErrReferralNotFound,
+			addReferral:   0,
+			statusCode:    http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+	conf.PassJWT = "JWTsecret"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoUser := mocks.NewMockUserRepo(ctrl)
+			repoRef := mocks.NewMockReferralRepo(ctrl)
+			userSrv := services.NewUserService(repoUser, entities.CredentialPolicy{})
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), time.Minute, time.Hour)
+
+			_ = repoSess.EXPECT().
+				AddSession(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(nil)
+			refSrv := services.NewReferralService(repoRef, decimal.NewFromInt(50), decimal.NewFromInt(25), 10)
+
+			refereeID, err := uuid.NewV7()
+			assert.NoError(t, err)
+			referrerID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoRef.EXPECT().
+				GetByReferralCode(gomock.Any(), strings.ToUpper(tt.referralCode)).
+				Times(1).
+				Return(referrerID, tt.referralError)
+
+			_ = repoUser.EXPECT().
+				AddUser(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(tt.addReferral).
+				Return(&refereeID, nil)
+
+			_ = repoRef.EXPECT().
+				AddReferral(gomock.Any(), referrerID, refereeID).
+				Times(tt.addReferral).
+				Return(nil)
+
+			user := entities.User{Login: "referee", Password: "qwerty123456", Referrer: tt.referralCode}
+			jsonUser, err := json.Marshal(user)
+			assert.NoError(t, err)
+
+			// add chi context
+			rctx := chi.NewRouteContext()
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/register", strings.NewReader(string(jsonUser)))
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			// Make request
+			regUser := NewHandlerRegister(conf, userSrv, refSrv, sessSrv)
+			regUser.SetUser(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestUserPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		stored     string
+		statusCode int
+		codes      []string
+	}{
+		{
+			name:       "Login normalized",
+			body:       `{"login": " Ｕｓｅｒ.Name ", "password": "correct-horse"}`,
+			stored:     "User.Name",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Short login and password (400)",
+			body:       `{"login": "ab", "password": "1"}`,
+			statusCode: http.StatusBadRequest,
+			codes:      []string{entities.LoginTooShort, entities.PasswordTooShort},
+		},
+		{
+			name:       "Empty login (400)",
+			body:       `{"login": "  ", "password": "correct-horse"}`,
+			statusCode: http.StatusBadRequest,
+			codes:      []string{entities.LoginEmpty},
+		},
+		{
+			name:       "Login with spaces (400)",
+			body:       `{"login": "john smith", "password": "correct-horse"}`,
+			statusCode: http.StatusBadRequest,
+			codes:      []string{entities.LoginInvalidChars},
+		},
+		{
+			name:       "Common password (400)",
+			body:       `{"login": "user", "password": "Password1"}`,
+			statusCode: http.StatusBadRequest,
+			codes:      []string{entities.PasswordCommon},
+		},
+		{
+			name:       "Denylist file password (400)",
+			body:       `{"login": "user", "password": "gophermart"}`,
+			statusCode: http.StatusBadRequest,
+			codes:      []string{entities.PasswordCommon},
+		},
+		{
+			name:       "Password is login (400)",
+			body:       `{"login": "username", "password": "USERNAME"}`,
+			statusCode: http.StatusBadRequest,
+			codes:      []string{entities.PasswordIsLogin},
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{PassJWT: "JWTsecret"}
+	readFile := func(string) ([]byte, error) { return []byte("GopherMart\n\n"), nil }
+	policy, err := config.ParseCredentialPolicy(3, 64, `^[\p{L}\p{N}._@-]+$`, 8, "denylist.txt", readFile)
+	assert.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoUser := mocks.NewMockUserRepo(ctrl)
+			userSrv := services.NewUserService(repoUser, policy)
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), time.Minute, time.Hour)
+
+			_ = repoSess.EXPECT().
+				AddSession(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(nil)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			calls := 0
+			if tt.statusCode == http.StatusOK {
+				calls = 1
+			}
+			_ = repoUser.EXPECT().
+				AddUser(gomock.Any(), tt.stored, gomock.Any()).
+				Times(calls).
+				Return(&userID, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/register", strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			refSrv := services.NewReferralService(mocks.NewMockReferralRepo(ctrl), decimal.Zero, decimal.Zero, 0)
+			regUser := NewHandlerRegister(conf, userSrv, refSrv, sessSrv)
+			regUser.SetUser(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if tt.statusCode == http.StatusBadRequest {
+				var valErr entities.ValidationError
+				err = json.NewDecoder(res.Body).Decode(&valErr)
+				assert.NoError(t, err)
+				codes := make([]string, 0)
+				for _, f := range valErr.Errors {
+					assert.NotEmpty(t, f.Field)
+					assert.NotEmpty(t, f.Message)
+					codes = append(codes, f.Code)
+				}
+				assert.Equal(t, tt.codes, codes)
+			}
+		})
+	}
+}
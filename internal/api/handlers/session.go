@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Refresh of access token and logout.
+type HandlerSession struct {
+	sessSrv *services.SessionService
+	conf    *config.Config
+}
+
+func NewHandlerSession(conf *config.Config, sessSrv *services.SessionService) *HandlerSession {
+	return &HandlerSession{sessSrv: sessSrv, conf: conf}
+}
+
+// Exchange refresh token for new access and refresh tokens.
+func (u *HandlerSession) Refresh(res http.ResponseWriter, req *http.Request) {
+	var rr entities.RefreshRequest
+	if err := json.NewDecoder(req.Body).Decode(&rr); err != nil || rr.RefreshToken == "" {
+		// 400
+		http.Error(res, "Refresh token not found.", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := u.sessSrv.Refresh(req.Context(), rr.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrSessionNotFound), errors.Is(err, entities.ErrSessionRevoked):
+			// 401
+			http.Error(res, "Session not found or expired.", http.StatusUnauthorized)
+		case errors.Is(err, entities.ErrRefreshReused):
+			// 401
+			zap.S().Infoln("Refresh token reused, session revoked.")
+			http.Error(res, "Session not found or expired.", http.StatusUnauthorized)
+		default:
+			// 500
+			errt := "Error during session refresh."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	res.Header().Add("Authorization", tokens.AccessToken)
+	writeJSON(res, tokens, http.StatusOK)
+}
+
+// Revoke session of access token.
+func (u *HandlerSession) Logout(res http.ResponseWriter, req *http.Request) {
+	// Get UserID from cxt values.
+	ctxConfig, ok := req.Context().Value(entities.MiddlwDTO{}).(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	err := u.sessSrv.Logout(req.Context(), ctxConfig.GetSessionID())
+	if err != nil {
+		// 500
+		errt := "Error during logout."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	zap.S().Infoln("User logged out: ", ctxConfig.GetUserID())
+	// 204
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// Public keys of access tokens for other services, JWK Set format.
+func (u *HandlerSession) JWKS(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(res, u.sessSrv.JWKS(), http.StatusOK)
+}
+
+// Start session for user, access token is set to Authorization header as well.
+func writeSession(res http.ResponseWriter, req *http.Request, sessSrv *services.SessionService, userID uuid.UUID) {
+	tokens, err := sessSrv.Login(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Can't start user's session."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Add("Authorization", tokens.AccessToken)
+	writeJSON(res, tokens, http.StatusOK)
+}
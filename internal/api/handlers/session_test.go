@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshSession(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		calls       int
+		rotateError error
+		statusCode  int
+	}{
+		{
+			name:       "Tokens refreshed",
+			body:       `{"refresh_token": "dG9rZW4"}`,
+			calls:      1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "Unknown token (401)",
+			body:        `{"refresh_token": "dG9rZW4"}`,
+			calls:       1,
+			rotateError: entities.ErrSessionNotFound,
+			statusCode:  http.StatusUnauthorized,
+		},
+		{
+			name:        "Session revoked (401)",
+			body:        `{"refresh_token": "dG9rZW4"}`,
+			calls:       1,
+			rotateError: entities.ErrSessionRevoked,
+			statusCode:  http.StatusUnauthorized,
+		},
+		{
+			name:        "Token reused (401)",
+			body:        `{"refresh_token": "dG9rZW4"}`,
+			calls:       1,
+			rotateError: entities.ErrRefreshReused,
+			statusCode:  http.StatusUnauthorized,
+		},
+		{
+			name:       "Without token (400)",
+			body:       `{}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{PassJWT: "JWTsecret", AccessTTL: time.Minute, RefreshTTL: time.Hour}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), conf.AccessTTL, conf.RefreshTTL)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+			sessionID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoSess.EXPECT().
+				RotateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, hash string, newHash string, _ time.Time) (*entities.Session, error) {
+					assert.NotEqual(t, hash, newHash)
+					if tt.rotateError != nil {
+						return nil, tt.rotateError
+					}
+					return &entities.Session{SessionID: sessionID, UserID: userID, RefreshHash: newHash}, nil
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/token/refresh", strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			sessHand := NewHandlerSession(conf, sessSrv)
+			sessHand.Refresh(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if tt.statusCode == http.StatusOK {
+				var tokens entities.TokenPair
+				err = json.NewDecoder(res.Body).Decode(&tokens)
+				require.NoError(t, err)
+				assert.NotEmpty(t, tokens.RefreshToken)
+				assert.NotEqual(t, "dG9rZW4", tokens.RefreshToken)
+				assert.Equal(t, res.Header.Get("Authorization"), tokens.AccessToken)
+
+				// New access token carries session id.
+				claims, err := services.ParseJWT(tokens.AccessToken, entities.NewHMACKeys(conf.PassJWT))
+				require.NoError(t, err)
+				assert.Equal(t, userID, claims.UserID)
+				assert.Equal(t, sessionID.String(), claims.ID)
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered bool
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Session revoked",
+			registered: true,
+			calls:      1,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Not logged in (401)",
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{PassJWT: "JWTsecret"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, entities.NewHMACKeys(conf.PassJWT), time.Minute, time.Hour)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+			sessionID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoSess.EXPECT().
+				RevokeSession(gomock.Any(), sessionID).
+				Times(tt.calls).
+				Return(nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/logout", nil)
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, tt.registered).WithSession(sessionID)))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			sessHand := NewHandlerSession(conf, sessSrv)
+			sessHand.Logout(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	// Signing Ed25519 key and previous RSA key, kept for verification only.
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	files := map[string][]byte{
+		"new.pem": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+		"old.pem": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDER}),
+	}
+	readFile := func(name string) ([]byte, error) { return files[name], nil }
+
+	tests := []struct {
+		name     string
+		keysConf string
+		kids     []string
+	}{
+		{
+			name:     "Rotated keys",
+			keysConf: "2024-05:new.pem,2024-04:old.pem",
+			kids:     []string{"2024-05", "2024-04"},
+		},
+		{
+			name: "HMAC secret is not published",
+			kids: []string{},
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{PassJWT: "JWTsecret"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keys, err := config.ParseJWTKeys(tt.keysConf, readFile)
+			require.NoError(t, err)
+			if keys == nil {
+				keys = entities.NewHMACKeys(conf.PassJWT)
+			}
+
+			// crete mock storege
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, keys, time.Minute, time.Hour)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/.well-known/jwks.json", nil)
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			sessHand := NewHandlerSession(conf, sessSrv)
+			sessHand.JWKS(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			var set entities.JWKSet
+			err = json.NewDecoder(res.Body).Decode(&set)
+			require.NoError(t, err)
+			kids := make([]string, 0)
+			for _, k := range set.Keys {
+				kids = append(kids, k.Kid)
+			}
+			assert.Equal(t, tt.kids, kids)
+
+			if len(set.Keys) == 0 {
+				return
+			}
+
+			// Token is signed with the first key and verified with published key.
+			assert.Equal(t, "OKP", set.Keys[0].Kty)
+			assert.Equal(t, "RSA", set.Keys[1].Kty)
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+			token, err := services.BuildJWTString(userID, uuid.Nil, entities.RoleUser, keys, time.Minute)
+			require.NoError(t, err)
+
+			x, err := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+			require.NoError(t, err)
+			claims := &entities.Claims{}
+			parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+				return ed25519.PublicKey(x), nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "2024-05", parsed.Header["kid"])
+			assert.Equal(t, "EdDSA", parsed.Method.Alg())
+			assert.Equal(t, userID, claims.UserID)
+
+			// Token of previous key is still valid.
+			old := jwt.NewWithClaims(jwt.SigningMethodRS256, entities.Claims{UserID: userID})
+			old.Header["kid"] = "2024-04"
+			oldToken, err := old.SignedString(rsaKey)
+			require.NoError(t, err)
+			claims, err = services.ParseJWT(oldToken, keys)
+			require.NoError(t, err)
+			assert.Equal(t, userID, claims.UserID)
+
+			// HMAC token and unknown kid are rejected.
+			_, err = services.ParseJWT(token, entities.NewHMACKeys(conf.PassJWT))
+			assert.Error(t, err)
+			hmac, err := services.BuildJWTString(userID, uuid.Nil, entities.RoleUser, entities.NewHMACKeys(conf.PassJWT), time.Minute)
+			require.NoError(t, err)
+			_, err = services.ParseJWT(hmac, keys)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Users, roles and orders of any user for admins.
+type HandlerUser struct {
+	adminSrv *services.AdminService
+	conf     *config.Config
+}
+
+func NewHandlerUser(conf *config.Config, adminSrv *services.AdminService) *HandlerUser {
+	return &HandlerUser{adminSrv: adminSrv, conf: conf}
+}
+
+func (u *HandlerUser) GetUser(res http.ResponseWriter, req *http.Request) {
+	userID, err := uuid.FromString(chi.URLParam(req, "userID"))
+	if err != nil {
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
+		return
+	}
+
+	user, err := u.adminSrv.GetUser(req.Context(), userID)
+	u.writeUser(res, user, err)
+}
+
+// Find user by login from query (?login=).
+func (u *HandlerUser) FindUser(res http.ResponseWriter, req *http.Request) {
+	login := req.URL.Query().Get("login")
+	if login == "" {
+		// 400
+		http.Error(res, "Login not found in query.", http.StatusBadRequest)
+		return
+	}
+
+	user, err := u.adminSrv.FindUser(req.Context(), login)
+	u.writeUser(res, user, err)
+}
+
+// Change user's role, sessions of user are revoked.
+func (u *HandlerUser) SetRole(res http.ResponseWriter, req *http.Request) {
+	admin, _ := req.Context().Value(entities.CtxAdminKey{}).(string)
+
+	userID, err := uuid.FromString(chi.URLParam(req, "userID"))
+	if err != nil {
+		// 404
+		http.Error(res, "User not found.", http.StatusNotFound)
+		return
+	}
+
+	var rr entities.RoleRequest
+	if err := json.NewDecoder(req.Body).Decode(&rr); err != nil {
+		// 400
+		http.Error(res, "Can't decode JSON", http.StatusBadRequest)
+		return
+	}
+
+	user, err := u.adminSrv.SetRole(req.Context(), admin, userID, rr.Role)
+	if errors.Is(err, entities.ErrWrongRole) {
+		// 422
+		http.Error(res, "Unknown role.", http.StatusUnprocessableEntity)
+		return
+	}
+	if err == nil {
+		zap.S().Infoln("User's role set by admin: ", userID, user.Role, admin)
+	}
+	u.writeUser(res, user, err)
+}
+
+// Order of any user with status history.
+func (u *HandlerUser) InspectOrder(res http.ResponseWriter, req *http.Request) {
+	order, err := u.adminSrv.InspectOrder(req.Context(), chi.URLParam(req, "number"))
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrOrderNotFound):
+			// 404
+			http.Error(res, "Order not found.", http.StatusNotFound)
+		default:
+			// 500
+			errt := "Cat't get order."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(res, order, http.StatusOK)
+}
+
+func (u *HandlerUser) writeUser(res http.ResponseWriter, user *entities.UserInfo, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrUserNotFound):
+			// 404
+			http.Error(res, "User not found.", http.StatusNotFound)
+		default:
+			// 500
+			errt := "Cat't get user."
+			zap.S().Errorln(errt, err)
+			http.Error(res, errt, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(res, user, http.StatusOK)
+}
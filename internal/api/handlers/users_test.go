@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/api/middlewares"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		adminKey   string
+		admin      string
+		statusCode int
+	}{
+		{
+			name:       "Admin by role",
+			role:       entities.RoleAdmin,
+			admin:      "user:",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Admin by key",
+			adminKey:   "key1",
+			admin:      "alice",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Regular user (403)",
+			role:       entities.RoleUser,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Regular user with wrong key (403)",
+			role:       entities.RoleUser,
+			adminKey:   "wrong",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Without token and key (401)",
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{PassJWT: "JWTsecret", AdminKeys: map[string]string{"key1": "alice"}}
+	keys := entities.NewHMACKeys(conf.PassJWT)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoSess := mocks.NewMockSessionRepo(ctrl)
+			sessSrv := services.NewSessionService(repoSess, keys, time.Minute, time.Hour)
+			repoAdmin := mocks.NewMockAdminRepo(ctrl)
+			adminSrv := services.NewAdminService(repoAdmin)
+
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+			sessionID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			_ = repoSess.EXPECT().
+				IsSessionActive(gomock.Any(), sessionID).
+				AnyTimes().
+				Return(true, nil)
+
+			calls := 0
+			if tt.statusCode == http.StatusOK {
+				calls = 1
+			}
+			_ = repoAdmin.EXPECT().
+				FindUser(gomock.Any(), "user").
+				Times(calls).
+				Return(&entities.UserInfo{UserID: userID, Login: "user", Role: entities.RoleUser}, nil)
+
+			// actor of admin's request
+			var admin string
+			users := NewHandlerUser(conf, adminSrv)
+			r := chi.NewRouter()
+			r.Use(middlewares.Auth(sessSrv))
+			r.Use(middlewares.Admin(conf.AdminKeys))
+			r.Get("/api/admin/users", func(res http.ResponseWriter, req *http.Request) {
+				admin, _ = req.Context().Value(entities.CtxAdminKey{}).(string)
+				users.FindUser(res, req)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/admin/users?login=user", nil)
+			if tt.role != "" {
+				token, err := services.BuildJWTString(userID, sessionID, tt.role, keys, time.Minute)
+				require.NoError(t, err)
+				req.Header.Add("Authorization", "Bearer "+token)
+			}
+			if tt.adminKey != "" {
+				req.Header.Add(middlewares.AdminKeyHeader, tt.adminKey)
+			}
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+			r.ServeHTTP(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if tt.statusCode == http.StatusOK {
+				if tt.role == entities.RoleAdmin {
+					tt.admin += userID.String()
+				}
+				assert.Equal(t, tt.admin, admin)
+
+				var user entities.UserInfo
+				err = json.NewDecoder(res.Body).Decode(&user)
+				require.NoError(t, err)
+				assert.Equal(t, userID, user.UserID)
+			}
+		})
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		roleError  error
+		statusCode int
+	}{
+		{
+			name:       "Role changed",
+			body:       `{"role": "admin"}`,
+			calls:      1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Unknown role (422)",
+			body:       `{"role": "root"}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "User not found (404)",
+			body:       `{"role": "admin"}`,
+			calls:      1,
+			roleError:  entities.ErrUserNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Broken JSON (400)",
+			body:       `{"role": `,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoAdmin := mocks.NewMockAdminRepo(ctrl)
+			adminSrv := services.NewAdminService(repoAdmin)
+
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			_ = repoAdmin.EXPECT().
+				SetRole(gomock.Any(), "admin", userID, entities.RoleAdmin).
+				Times(tt.calls).
+				DoAndReturn(func(_ context.Context, _ string, userID uuid.UUID, role string) (*entities.UserInfo, error) {
+					if tt.roleError != nil {
+						return nil, tt.roleError
+					}
+					return &entities.UserInfo{UserID: userID, Login: "user", Role: role}, nil
+				})
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userID", userID.String())
+			req := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/admin/users/"+userID.String()+"/role", strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(context.WithValue(ctx, entities.CtxAdminKey{}, "admin"))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			users := NewHandlerUser(conf, adminSrv)
+			users.SetRole(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestInspectOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderError error
+		statusCode int
+	}{
+		{
+			name:       "Order of other user",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Order not found (404)",
+			orderError: entities.ErrOrderNotFound,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoAdmin := mocks.NewMockAdminRepo(ctrl)
+			adminSrv := services.NewAdminService(repoAdmin)
+
+			userID, err := uuid.NewV7()
+			require.NoError(t, err)
+
+			var detail *entities.OrderDetail
+			if tt.orderError == nil {
+				order := &entities.Order{UserID: userID, OrderNr: "12345678903", Status: entities.Status(entities.PROCESSED), Uploaded: time.Now()}
+				detail = &entities.OrderDetail{Order: order, History: []entities.StatusChange{{Status: entities.Status(entities.NEW), Created: time.Now()}}}
+			}
+			_ = repoAdmin.EXPECT().
+				InspectOrder(gomock.Any(), "12345678903").
+				Return(detail, tt.orderError)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("number", "12345678903")
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/admin/orders/12345678903", nil)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			users := NewHandlerUser(conf, adminSrv)
+			users.InspectOrder(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			defer res.Body.Close()
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			if tt.statusCode == http.StatusOK {
+				var inspection struct {
+					UserID uuid.UUID `json:"user_id"`
+					Order  struct {
+						Number  string            `json:"number"`
+						History []json.RawMessage `json:"history"`
+					} `json:"order"`
+				}
+				err = json.NewDecoder(res.Body).Decode(&inspection)
+				require.NoError(t, err)
+				assert.Equal(t, userID, inspection.UserID)
+				assert.Equal(t, "12345678903", inspection.Order.Number)
+				assert.Len(t, inspection.Order.History, 1)
+			}
+		})
+	}
+}
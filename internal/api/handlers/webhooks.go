@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// User's outbound webhooks.
+type HandlerWebhook struct {
+	hookSrv *services.WebhookService
+	conf    *config.Config
+}
+
+func NewHandlerWebhook(conf *config.Config, hookSrv *services.WebhookService) *HandlerWebhook {
+	return &HandlerWebhook{hookSrv: hookSrv, conf: conf}
+}
+
+func (u *HandlerWebhook) AddWebhook(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	var wr entities.WebhookRequest
+	if err := json.NewDecoder(req.Body).Decode(&wr); err != nil {
+		// If can't decode 400
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	webhook := entities.NewWebhook(userID, &wr)
+	if !webhook.IsValid(u.conf.WebhookPrivate) {
+		// 422
+		errt := "Webhook not valid: http(s) url of public host, secret of 16 chars and known events are required."
+		zap.S().Debugln(errt, wr.URL, wr.Events)
+		http.Error(res, errt, http.StatusUnprocessableEntity)
+		return
+	}
+
+	err := u.hookSrv.AddWebhook(req.Context(), webhook)
+	if err != nil {
+		// 500
+		errt := "Error during webhook creation."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(res, webhook, http.StatusCreated)
+}
+
+func (u *HandlerWebhook) GetWebhooks(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	webhooks, err := u.hookSrv.GetWebhooks(req.Context(), userID)
+	if err != nil {
+		// 500
+		errt := "Cat't get webhooks."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, webhooks, http.StatusOK)
+}
+
+func (u *HandlerWebhook) DeleteWebhook(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	webhookID, err := uuid.FromString(chi.URLParam(req, "webhookID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Webhook not found.", http.StatusNotFound)
+		return
+	}
+
+	err = u.hookSrv.DeleteWebhook(req.Context(), userID, webhookID)
+	if err != nil {
+		if errors.Is(err, entities.ErrWebhookNotFound) {
+			// 404
+			http.Error(res, "Webhook not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Error during webhook deletion."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// Recent deliveries of webhook with response codes.
+func (u *HandlerWebhook) GetDeliveries(res http.ResponseWriter, req *http.Request) {
+	// get UserID from cxt values
+	ctxConfigVal := req.Context().Value(entities.MiddlwDTO{})
+	ctxConfig, ok := ctxConfigVal.(entities.MiddlwDTO)
+	if !ok {
+		errt := "Cat't get MiddlwDTO from context."
+		zap.S().Errorln(errt)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	// Check from middleware is user authorized 401
+	if !ctxConfig.IsRegistered() {
+		http.Error(res, "JWT not found.", http.StatusUnauthorized)
+		return
+	}
+
+	userID := ctxConfig.GetUserID()
+
+	webhookID, err := uuid.FromString(chi.URLParam(req, "webhookID"))
+	if err != nil {
+		// 404
+		http.Error(res, "Webhook not found.", http.StatusNotFound)
+		return
+	}
+
+	deliveries, err := u.hookSrv.GetDeliveries(req.Context(), userID, webhookID)
+	if err != nil {
+		if errors.Is(err, entities.ErrWebhookNotFound) {
+			// 404
+			http.Error(res, "Webhook not found.", http.StatusNotFound)
+			return
+		}
+		// 500
+		errt := "Cat't get webhook deliveries."
+		zap.S().Errorln(errt, err)
+		http.Error(res, errt, http.StatusInternalServerError)
+		return
+	}
+
+	if len(deliveries) == 0 {
+		// 204
+		http.Error(res, "No content", http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, deliveries, http.StatusOK)
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shulganew/gophermart/internal/app"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"github.com/shulganew/gophermart/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Webhook for all events",
+			body:       `{"url": "https://merchant.example/hooks", "secret": "0123456789abcdef"}`,
+			calls:      1,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Webhook for processed orders",
+			body:       `{"url": "http://merchant.example/hooks", "secret": "0123456789abcdef", "events": ["order.processed"]}`,
+			calls:      1,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Unknown event (422)",
+			body:       `{"url": "https://merchant.example/hooks", "secret": "0123456789abcdef", "events": ["order.lost"]}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Short secret (422)",
+			body:       `{"url": "https://merchant.example/hooks", "secret": "123"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Loopback url (422)",
+			body:       `{"url": "http://127.0.0.1:5432/hooks", "secret": "0123456789abcdef"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Link-local metadata url (422)",
+			body:       `{"url": "http://169.254.169.254/latest/meta-data", "secret": "0123456789abcdef"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Private url (422)",
+			body:       `{"url": "https://[fd00::1]/hooks", "secret": "0123456789abcdef"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Localhost url (422)",
+			body:       `{"url": "http://LocalHost:8080/hooks", "secret": "0123456789abcdef"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Not http url (422)",
+			body:       `{"url": "ftp://merchant.example/hooks", "secret": "0123456789abcdef"}`,
+			calls:      0,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	app.InitLog()
+	conf := &config.Config{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// crete mock storege
+			repoHook := mocks.NewMockWebhookRepo(ctrl)
+			hookSrv := services.NewWebhookService(repoHook, false)
+
+			userID, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			_ = repoHook.EXPECT().
+				AddWebhook(gomock.Any(), gomock.Any()).
+				Times(tt.calls).
+				Return(nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/user/webhooks", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, true)))
+			req.Header.Add("Content-Type", "application/json")
+
+			// create status recorder
+			resRecord := httptest.NewRecorder()
+
+			hookHand := NewHandlerWebhook(conf, hookSrv)
+			hookHand.AddWebhook(resRecord, req)
+
+			// get result
+			res := resRecord.Result()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			err = res.Body.Close()
+			assert.NoError(t, err)
+
+			// check answer code
+			t.Log("StatusCode test: ", tt.statusCode, " server: ", res.StatusCode)
+			assert.Equal(t, tt.statusCode, res.StatusCode)
+
+			// secret is never returned
+			assert.NotContains(t, string(body), "0123456789abcdef")
+		})
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		answer   int
+		attempts int
+		// Merchant's test server listens on loopback.
+		private bool
+		status  entities.DeliveryStatus
+	}{
+		{
+			name:     "Delivered",
+			answer:   http.StatusOK,
+			attempts: 0,
+			private:  true,
+			status:   entities.DeliveryDelivered,
+		},
+		{
+			name:     "Merchant error, retry later",
+			answer:   http.StatusInternalServerError,
+			attempts: 0,
+			private:  true,
+			status:   entities.DeliveryPending,
+		},
+		{
+			name:     "Last attempt failed",
+			answer:   http.StatusBadGateway,
+			attempts: 7,
+			private:  true,
+			status:   entities.DeliveryFailed,
+		},
+		{
+			name:     "Loopback target is not dialed",
+			attempts: 0,
+			status:   entities.DeliveryFailed,
+		},
+	}
+
+	app.InitLog()
+	secret := "0123456789abcdef"
+	payload := []byte(`{"event":"order.processed","data":{"number":"12345678903","status":"PROCESSED","accrual":500}}`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log("Test name: ", tt.name)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// merchant's server checks signature
+			merchant := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.True(t, tt.private, "private target must not be dialed")
+				assert.Equal(t, entities.WebhookOrderProcessed, req.Header.Get(entities.WebhookEventHeader))
+				sign := services.Sign(secret, req.Header.Get(entities.WebhookTimestampHeader), body)
+				assert.Equal(t, sign, req.Header.Get(entities.WebhookSignatureHeader))
+				res.WriteHeader(tt.answer)
+			}))
+			defer merchant.Close()
+
+			// crete mock storege
+			repoHook := mocks.NewMockWebhookRepo(ctrl)
+			hookSrv := services.NewWebhookService(repoHook, tt.private)
+
+			delivery := entities.Delivery{
+				DeliveryID: uuid.Must(uuid.NewV4()),
+				Event:      entities.WebhookOrderProcessed,
+				Payload:    payload,
+				Status:     entities.DeliveryPending,
+				Attempts:   tt.attempts,
+				URL:        merchant.URL,
+				Secret:     secret,
+			}
+
+			// each delivery is taken with its own lease until no due deliveries left
+			gomock.InOrder(
+				repoHook.EXPECT().
+					TakeDeliveries(gomock.Any(), 1, gomock.Any()).
+					Return([]entities.Delivery{delivery}, nil),
+				repoHook.EXPECT().
+					TakeDeliveries(gomock.Any(), 1, gomock.Any()).
+					Return([]entities.Delivery{}, nil),
+			)
+
+			_ = repoHook.EXPECT().
+				UpdateDelivery(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, d *entities.Delivery) error {
+					assert.Equal(t, tt.status, d.Status)
+					assert.Equal(t, tt.attempts+1, d.Attempts)
+					if !tt.private {
+						assert.Nil(t, d.ResponseCode)
+						assert.Contains(t, d.Error, entities.ErrWebhookTarget.Error())
+						return nil
+					}
+					require.NotNil(t, d.ResponseCode)
+					assert.Equal(t, tt.answer, *d.ResponseCode)
+					if tt.status == entities.DeliveryPending {
+						assert.True(t, d.NextAttempt.After(time.Now()))
+					}
+					return nil
+				})
+
+			hookSrv.Deliver(context.Background())
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Header with admin API key.
+const AdminKeyHeader = "X-Admin-Key"
+
+// Allow access only with admin key or access token of user with admin role, set admin's name to context.
+// Must be used after Auth, admins by role are named as user:<user id>.
+func Admin(keys map[string]string) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		byRole := RequireRole(entities.RoleAdmin)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctxConfig, _ := req.Context().Value(entities.MiddlwDTO{}).(entities.MiddlwDTO)
+			ctx := context.WithValue(req.Context(), entities.CtxAdminKey{}, "user:"+ctxConfig.GetUserID().String())
+			h.ServeHTTP(res, req.WithContext(ctx))
+		}))
+
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			key := req.Header.Get(AdminKeyHeader)
+			if key == "" {
+				byRole.ServeHTTP(res, req)
+				return
+			}
+
+			admin, ok := keys[key]
+			if !ok {
+				zap.S().Infoln("Wrong admin key, access denied.")
+				http.Error(res, "Access denied.", http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), entities.CtxAdminKey{}, admin)
+			h.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
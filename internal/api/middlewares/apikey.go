@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shulganew/gophermart/internal/entities"
+	"github.com/shulganew/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+// Header with merchant API key.
+const APIKeyHeader = "X-Api-Key"
+
+// Set merchant's key to context, requests without key are passed to JWT authorization.
+func APIKey(merchSrv *services.MerchantService) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			secret := req.Header.Get(APIKeyHeader)
+			if secret == "" {
+				h.ServeHTTP(res, req)
+				return
+			}
+
+			key, err := merchSrv.Authenticate(req.Context(), secret)
+			if err != nil {
+				zap.S().Infoln("Merchant key not valid.", err)
+				http.Error(res, "API key not valid.", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), entities.CtxMerchantKey{}, key)
+			h.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
+
+// Allow access only with merchant key of scope, must be used after APIKey.
+func RequireScope(scope string) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			key, ok := req.Context().Value(entities.CtxMerchantKey{}).(*entities.MerchantKey)
+			if !ok {
+				http.Error(res, "API key not found.", http.StatusUnauthorized)
+				return
+			}
+
+			if !key.HasScope(scope) {
+				zap.S().Infoln("Merchant key has no scope: ", key.Actor(), scope)
+				http.Error(res, "Access denied.", http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(res, req)
+		})
+	}
+}
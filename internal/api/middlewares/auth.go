@@ -10,27 +10,25 @@ import (
 	"go.uber.org/zap"
 )
 
-func Auth(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		passVal := req.Context().Value(entities.CtxPassKey{})
-		pass, ok := passVal.(string)
-		if !ok {
-			zap.S().Errorln("Can't git pass key from context.")
-			h.ServeHTTP(res, req)
-			return
-		}
-		jwt, isSet := services.GetHeaderJWT(req.Header)
+// Set user, session and role of access token to context, revoked sessions are not authorized.
+func Auth(sessSrv *services.SessionService) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			jwt, isSet := services.GetHeaderJWT(req.Header)
 
-		var userID uuid.UUID
-		var err error
-		if isSet {
-			userID, err = services.GetUserIDJWT(jwt, pass)
-			if err != nil {
-				zap.S().Infoln("Can't get user UUID form JWT.", err)
-				isSet = false
+			var userID, sessionID uuid.UUID
+			var role string
+			var err error
+			if isSet {
+				userID, sessionID, role, err = sessSrv.Authenticate(req.Context(), jwt)
+				if err != nil {
+					zap.S().Infoln("Can't get user UUID form JWT.", err)
+					isSet = false
+				}
 			}
-		}
-		ctx := context.WithValue(req.Context(), entities.MiddlwDTO{}, entities.NewMiddlwDTO(userID, isSet))
-		h.ServeHTTP(res, req.WithContext(ctx))
-	})
+			dto := entities.NewMiddlwDTO(userID, isSet).WithSession(sessionID).WithRole(role)
+			ctx := context.WithValue(req.Context(), entities.MiddlwDTO{}, dto)
+			h.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
 }
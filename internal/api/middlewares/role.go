@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Allow access only to users with one of roles, must be used after Auth.
+func RequireRole(roles ...string) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctxConfig, ok := req.Context().Value(entities.MiddlwDTO{}).(entities.MiddlwDTO)
+			if !ok || !ctxConfig.IsRegistered() {
+				http.Error(res, "JWT not found.", http.StatusUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if ctxConfig.GetRole() == role {
+					h.ServeHTTP(res, req)
+					return
+				}
+			}
+
+			zap.S().Infoln("User's role has no access: ", ctxConfig.GetUserID(), ctxConfig.GetRole())
+			http.Error(res, "Access denied.", http.StatusForbidden)
+		})
+	}
+}
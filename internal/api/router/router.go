@@ -1,7 +1,6 @@
 package router
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -17,30 +16,114 @@ func RouteMarket(application *app.Application) (r *chi.Mux) {
 	r = chi.NewRouter()
 
 	r.Route("/", func(r chi.Router) {
-		// send password for enctription to middlewares
-		r.Use(func(h http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				ctx := context.WithValue(r.Context(), entities.CtxPassKey{}, conf.PassJWT)
-				h.ServeHTTP(w, r.WithContext(ctx))
-			})
-		})
-
-		userReg := handlers.NewHandlerRegister(conf, application.UserService())
+		userReg := handlers.NewHandlerRegister(conf, application.UserService(), application.ReferralService(), application.SessionService())
 		r.Post("/api/user/register", http.HandlerFunc(userReg.SetUser))
 
-		userLogin := handlers.NewHandlerLogin(conf, application.UserService())
+		userLogin := handlers.NewHandlerLogin(conf, application.UserService(), application.SessionService(), application.LoginGuard())
 		r.Post("/api/user/login", http.HandlerFunc(userLogin.LoginUser))
 
+		session := handlers.NewHandlerSession(conf, application.SessionService())
+		r.Post("/api/user/token/refresh", http.HandlerFunc(session.Refresh))
+		r.Get("/.well-known/jwks.json", http.HandlerFunc(session.JWKS))
+
+		password := handlers.NewHandlerPassword(conf, application.PasswordService(), application.LoginGuard())
+		r.Post("/api/user/password/reset", http.HandlerFunc(password.RequestReset))
+		r.Post("/api/user/password/reset/confirm", http.HandlerFunc(password.ConfirmReset))
+
 		r.Route("/api/user", func(r chi.Router) {
-			r.Use(middlewares.Auth)
+			r.Use(middlewares.Auth(application.SessionService()))
+			r.Post("/logout", http.HandlerFunc(session.Logout))
+			r.Post("/password", http.HandlerFunc(password.ChangePassword))
 			orderHand := handlers.NewHandlerOrder(conf, application.CalculationService(), application.AccrualService(), application.OrderService())
 			r.Post("/orders", http.HandlerFunc(orderHand.AddOrder))
+			r.Post("/orders/batch", http.HandlerFunc(orderHand.AddOrders))
 			r.Get("/orders", http.HandlerFunc(orderHand.GetOrders))
+			r.Get("/orders/{number}", http.HandlerFunc(orderHand.GetOrder))
+			r.Delete("/orders/{number}", http.HandlerFunc(orderHand.DeleteOrder))
 
 			balance := handlers.NewHandlerBalance(conf, application.CalculationService(), application.OrderService())
 			r.Get("/balance", http.HandlerFunc(balance.GetBalance))
 			r.Post("/balance/withdraw", http.HandlerFunc(balance.SetWithdraw))
 			r.Get("/withdrawals", http.HandlerFunc(balance.GetWithdrawals))
+			r.Get("/history", http.HandlerFunc(balance.GetHistory))
+
+			holds := handlers.NewHandlerHold(conf, application.HoldService(), application.CalculationService(), application.OrderService())
+			r.Post("/balance/holds", http.HandlerFunc(holds.CreateHold))
+			r.Get("/balance/holds", http.HandlerFunc(holds.GetHolds))
+			r.Post("/balance/holds/{holdID}/capture", http.HandlerFunc(holds.CaptureHold))
+			r.Post("/balance/holds/{holdID}/void", http.HandlerFunc(holds.VoidHold))
+
+			profile := handlers.NewHandlerProfile(conf, application.TierService())
+			r.Get("/profile", http.HandlerFunc(profile.GetProfile))
+
+			referrals := handlers.NewHandlerReferral(conf, application.ReferralService())
+			r.Get("/referrals", http.HandlerFunc(referrals.GetReferrals))
+
+			events := handlers.NewHandlerEvents(conf, application.EventBus())
+			r.Get("/events", http.HandlerFunc(events.Stream))
+
+			webhooks := handlers.NewHandlerWebhook(conf, application.WebhookService())
+			r.Post("/webhooks", http.HandlerFunc(webhooks.AddWebhook))
+			r.Get("/webhooks", http.HandlerFunc(webhooks.GetWebhooks))
+			r.Delete("/webhooks/{webhookID}", http.HandlerFunc(webhooks.DeleteWebhook))
+			r.Get("/webhooks/{webhookID}/deliveries", http.HandlerFunc(webhooks.GetDeliveries))
+
+			disputes := handlers.NewHandlerDispute(conf, application.DisputeService())
+			r.Post("/disputes", http.HandlerFunc(disputes.AddDispute))
+			r.Get("/disputes", http.HandlerFunc(disputes.GetDisputes))
+		})
+
+		r.Route("/api/merchant", func(r chi.Router) {
+			r.Use(middlewares.APIKey(application.MerchantService()))
+			orderHand := handlers.NewHandlerOrder(conf, application.CalculationService(), application.AccrualService(), application.OrderService())
+			merchant := handlers.NewHandlerMerchant(conf, application.MerchantService(), orderHand)
+			r.With(middlewares.RequireScope(entities.ScopeOrdersWrite)).Post("/orders", http.HandlerFunc(merchant.AddOrder))
+			r.With(middlewares.RequireScope(entities.ScopeOrdersRead)).Get("/orders/{number}", http.HandlerFunc(merchant.GetOrder))
+		})
+
+		r.Route("/api/admin", func(r chi.Router) {
+			r.Use(middlewares.Auth(application.SessionService()))
+			r.Use(middlewares.Admin(conf.AdminKeys))
+
+			campaigns := handlers.NewHandlerCampaign(conf, application.CampaignService())
+			r.Post("/campaigns", http.HandlerFunc(campaigns.AddCampaign))
+			r.Get("/campaigns", http.HandlerFunc(campaigns.GetCampaigns))
+			r.Post("/campaigns/{campaignID}/pause", http.HandlerFunc(campaigns.PauseCampaign))
+			r.Post("/campaigns/{campaignID}/resume", http.HandlerFunc(campaigns.ResumeCampaign))
+			r.Get("/campaigns/{campaignID}/report", http.HandlerFunc(campaigns.GetReport))
+
+			adjustments := handlers.NewHandlerAdjustment(conf, application.AdjustmentService())
+			r.Post("/users/{userID}/adjustments", http.HandlerFunc(adjustments.AddAdjustment))
+			r.Get("/adjustments", http.HandlerFunc(adjustments.GetAdjustments))
+			r.Post("/adjustments/{adjustmentID}/approve", http.HandlerFunc(adjustments.Approve))
+			r.Post("/adjustments/{adjustmentID}/reject", http.HandlerFunc(adjustments.Reject))
+			r.Get("/audit", http.HandlerFunc(adjustments.GetAudit))
+
+			balance := handlers.NewHandlerBalance(conf, application.CalculationService(), application.OrderService())
+			r.Get("/users/{userID}/balance", http.HandlerFunc(balance.GetUserBalance))
+			r.Get("/users/{userID}/history", http.HandlerFunc(balance.GetUserHistory))
+
+			users := handlers.NewHandlerUser(conf, application.AdminService())
+			r.Get("/users", http.HandlerFunc(users.FindUser))
+			r.Get("/users/{userID}", http.HandlerFunc(users.GetUser))
+			r.Put("/users/{userID}/role", http.HandlerFunc(users.SetRole))
+			r.Get("/orders/{number}", http.HandlerFunc(users.InspectOrder))
+
+			orders := handlers.NewHandlerOrder(conf, application.CalculationService(), application.AccrualService(), application.OrderService())
+			r.Get("/users/{userID}/orders", http.HandlerFunc(orders.GetUserOrders))
+
+			disputes := handlers.NewHandlerDispute(conf, application.DisputeService())
+			r.Get("/disputes", http.HandlerFunc(disputes.GetAllDisputes))
+			r.Post("/disputes/{disputeID}/reassign", http.HandlerFunc(disputes.Reassign))
+			r.Post("/disputes/{disputeID}/reject", http.HandlerFunc(disputes.Reject))
+
+			logins := handlers.NewHandlerLogin(conf, application.UserService(), application.SessionService(), application.LoginGuard())
+			r.Post("/logins/{login}/unlock", http.HandlerFunc(logins.Unlock))
+
+			merchants := handlers.NewHandlerMerchant(conf, application.MerchantService(), orders)
+			r.Post("/merchant-keys", http.HandlerFunc(merchants.AddKey))
+			r.Get("/merchant-keys", http.HandlerFunc(merchants.GetKeys))
+			r.Delete("/merchant-keys/{keyID}", http.HandlerFunc(merchants.RevokeKey))
 		})
 	})
 
@@ -1,20 +1,40 @@
 package config
 
 import (
+	"crypto"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/shopspring/decimal"
 	"github.com/shulganew/gophermart/internal/api/validators"
+	"github.com/shulganew/gophermart/internal/entities"
 	"go.uber.org/zap"
 )
 
 // Check Acceral service every X sec.
 const CheckAccrual = 1
 
-const DataBaseType = "postgres"
+// Check stale holds every X sec.
+const CheckHolds = 10
+
+// Check returns of processed orders every X sec.
+const CheckReturns = 60
+
+// Last user's events kept for resume of event stream.
+const EventsBuffer = 1000
 
-const TokenExp = time.Hour * 3600
+// Send webhook deliveries every X sec.
+const CheckWebhooks = 5
+
+const DataBaseType = "postgres"
 
 type Config struct {
 	//flag -a, Market address
@@ -27,6 +47,60 @@ type Config struct {
 	DSN string
 
 	PassJWT string
+
+	// Keys of access JWT, HMAC with PassJWT if no keys set
+	JWTKeys *entities.JWTKeys
+
+	// Lifetime of access JWT and of session's refresh token
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+
+	// Rules of login and password of new users
+	CredentialPolicy entities.CredentialPolicy
+
+	// Limits of failed logins
+	LoginPolicy entities.LoginPolicy
+
+	// Lifetime of password reset token
+	ResetTTL time.Duration
+
+	// File of notifications for local development, empty - to log
+	NotifyFile string
+
+	// Time to live of bonuses hold
+	HoldTTL time.Duration
+
+	// Loyalty tiers sorted by threshold
+	Tiers []entities.Tier
+
+	// Rolling window for tier's accruals
+	TierWindow time.Duration
+
+	// Admin API keys: key -> admin name
+	AdminKeys map[string]string
+
+	// Referral program rewards and max rewarded referrals for referrer
+	ReferrerBonus decimal.Decimal
+	RefereeBonus  decimal.Decimal
+	ReferralLimit int
+
+	// Withdrawal limits
+	WithdrawPolicy entities.WithdrawPolicy
+
+	// Manual adjustments above threshold need approval of second admin, 0 - no approval
+	ApprovalThreshold decimal.Decimal
+
+	// Processed orders are checked for returns during window, 0 - no checks
+	ReturnWindow time.Duration
+
+	// Forward goods of uploaded orders to Accrual system
+	RegisterOrders bool
+
+	// Webhooks to loopback, private and link-local addresses, for local development
+	WebhookPrivate bool
+
+	// Order number formats of partner stores
+	OrderValidator entities.OrderValidator
 }
 
 func InitConfig() *Config {
@@ -36,6 +110,39 @@ func InitConfig() *Config {
 	loyaltyAddress := flag.String("r", "localhost:8090", "Service Loyality address")
 	dsnf := flag.String("d", "", "Data Source Name for DataBase connection")
 	authJWT := flag.String("p", "JWTsecret", "JWT private key")
+	accessTTL := flag.Duration("access-ttl", time.Minute*15, "Time to live of access token")
+	refreshTTL := flag.Duration("refresh-ttl", time.Hour*24*30, "Time to live of refresh token")
+	jwtKeys := flag.String("jwt-keys", "", "JWT keys kid:path to PEM, the first key signs tokens, empty - HMAC with -p")
+	loginMin := flag.Int("login-min", 3, "Min length of login")
+	loginMax := flag.Int("login-max", 64, "Max length of login")
+	loginPattern := flag.String("login-pattern", `^[\p{L}\p{N}._@-]+$`, "Allowed characters of login, regexp")
+	passwordMin := flag.Int("password-min", 8, "Min length of password")
+	denylist := flag.String("password-denylist", "", "File of denied passwords, one per line")
+	loginDelay := flag.Duration("login-delay", time.Second, "Delay after failed login, doubled after each next failure")
+	loginMaxDelay := flag.Duration("login-max-delay", time.Second*30, "Max delay after failed login")
+	loginLimit := flag.Int("login-limit", 5, "Failed attempts before login lockout, 0 - no lockout")
+	ipLimit := flag.Int("ip-limit", 50, "Failed attempts before IP lockout, 0 - no lockout")
+	loginLockout := flag.Duration("login-lockout", time.Minute*15, "Lockout time of login or IP")
+	resetTTL := flag.Duration("reset-ttl", time.Hour, "Time to live of password reset token")
+	notifyFile := flag.String("notify-file", "", "File of notifications to users, empty - log")
+	holdTTL := flag.Duration("hold-ttl", time.Minute*30, "Time to live of bonuses hold")
+	tiers := flag.String("tiers", "bronze:0:1,silver:1000:1.25,gold:5000:1.5", "Loyalty tiers name:threshold:multiplier")
+	tierWindow := flag.Duration("tier-window", time.Hour*24*90, "Rolling window for tier's accruals")
+	adminKeys := flag.String("admin-keys", "", "Admin API keys name:key")
+	referrerBonus := flag.Float64("referrer-bonus", 50, "Referral program bonus for referrer")
+	refereeBonus := flag.Float64("referee-bonus", 25, "Referral program bonus for referee")
+	referralLimit := flag.Int("referral-limit", 10, "Max rewarded referrals for referrer")
+	withdrawMin := flag.Float64("withdraw-min", 0, "Minimal withdrawal, 0 - no limit")
+	withdrawMax := flag.Float64("withdraw-max", 0, "Maximal withdrawal, 0 - no limit")
+	withdrawDaily := flag.Float64("withdraw-daily", 0, "Withdrawals per day limit, 0 - no limit")
+	withdrawMonthly := flag.Float64("withdraw-monthly", 0, "Withdrawals per month limit, 0 - no limit")
+	withdrawShare := flag.Float64("withdraw-share", 0, "Max share of the order paid with bonuses, 0 - no limit")
+	withdrawCooldown := flag.Duration("withdraw-cooldown", 0, "Time after registration without withdrawals")
+	approvalThreshold := flag.Float64("approval-threshold", 1000, "Manual adjustments above threshold need second admin approval, 0 - no approval")
+	returnWindow := flag.Duration("return-window", time.Hour*24*30, "Window for returns of processed orders, 0 - no returns")
+	registerOrders := flag.Bool("register-orders", false, "Forward goods of uploaded orders to Accrual system")
+	webhookPrivate := flag.Bool("webhook-private", false, "Allow webhooks to loopback, private and link-local addresses")
+	orderRules := flag.String("order-rules", "", "Order number rules prefix:length:scheme, empty - Luhn numbers")
 
 	flag.Parse()
 
@@ -53,6 +160,113 @@ func InitConfig() *Config {
 
 	// JWT password for users auth
 	config.PassJWT = *authJWT
+	config.AccessTTL = *accessTTL
+	config.RefreshTTL = *refreshTTL
+
+	// JWT signing and verification keys
+	keysConf := *jwtKeys
+	if envKeys, exist := os.LookupEnv(("JWT_KEYS")); exist {
+		keysConf = envKeys
+		zap.S().Infoln("Set JWT keys from evn JWT_KEYS: ", keysConf)
+	}
+	keys, err := ParseJWTKeys(keysConf, os.ReadFile)
+	if err != nil {
+		zap.S().Errorln("Can't load JWT keys: ", err)
+		os.Exit(65)
+	}
+	if keys == nil {
+		keys = entities.NewHMACKeys(config.PassJWT)
+	}
+	config.JWTKeys = keys
+
+	// Login and password policy
+	config.CredentialPolicy, err = ParseCredentialPolicy(*loginMin, *loginMax, *loginPattern, *passwordMin, *denylist, os.ReadFile)
+	if err != nil {
+		zap.S().Errorln("Can't make login and password policy: ", err)
+		os.Exit(65)
+	}
+
+	// Brute-force protection of login
+	config.LoginPolicy = entities.LoginPolicy{
+		Delay:      *loginDelay,
+		MaxDelay:   *loginMaxDelay,
+		LoginLimit: *loginLimit,
+		IPLimit:    *ipLimit,
+		Lockout:    *loginLockout,
+	}
+
+	// Password reset
+	config.ResetTTL = *resetTTL
+	config.NotifyFile = *notifyFile
+
+	// Bonuses hold expiration
+	config.HoldTTL = *holdTTL
+
+	// Loyalty tiers
+	config.TierWindow = *tierWindow
+	tiersConf := *tiers
+	if envTiers, exist := os.LookupEnv(("TIERS")); exist {
+		tiersConf = envTiers
+		zap.S().Infoln("Set tiers from evn TIERS: ", tiersConf)
+	}
+	parsed, err := ParseTiers(tiersConf)
+	if err != nil {
+		zap.S().Errorln("Can't parse loyalty tiers: ", err)
+		os.Exit(65)
+	}
+	config.Tiers = parsed
+
+	// Admin API access
+	adminConf := *adminKeys
+	if envAdmin, exist := os.LookupEnv(("ADMIN_KEYS")); exist {
+		adminConf = envAdmin
+		zap.S().Infoln("Set admin keys from evn ADMIN_KEYS")
+	}
+	config.AdminKeys, err = ParseAdminKeys(adminConf)
+	if err != nil {
+		zap.S().Errorln("Can't parse admin keys: ", err)
+		os.Exit(65)
+	}
+
+	// Referral program
+	config.ReferrerBonus = decimal.NewFromFloat(*referrerBonus)
+	config.RefereeBonus = decimal.NewFromFloat(*refereeBonus)
+	config.ReferralLimit = *referralLimit
+
+	// Withdrawal policy
+	config.WithdrawPolicy = entities.WithdrawPolicy{
+		Min:      decimal.NewFromFloat(*withdrawMin),
+		Max:      decimal.NewFromFloat(*withdrawMax),
+		Daily:    decimal.NewFromFloat(*withdrawDaily),
+		Monthly:  decimal.NewFromFloat(*withdrawMonthly),
+		Share:    decimal.NewFromFloat(*withdrawShare),
+		Cooldown: *withdrawCooldown,
+	}
+
+	// Manual adjustments
+	config.ApprovalThreshold = decimal.NewFromFloat(*approvalThreshold)
+
+	// Returns of processed orders
+	config.ReturnWindow = *returnWindow
+
+	// Registration of orders in Accrual system
+	config.RegisterOrders = *registerOrders
+
+	// Webhook targets
+	config.WebhookPrivate = *webhookPrivate
+
+	// Order number validation
+	rulesConf := *orderRules
+	if envRules, exist := os.LookupEnv(("ORDER_RULES")); exist {
+		rulesConf = envRules
+		zap.S().Infoln("Set order rules from evn ORDER_RULES: ", rulesConf)
+	}
+	rules, err := ParseOrderRules(rulesConf)
+	if err != nil {
+		zap.S().Errorln("Can't parse order rules: ", err)
+		os.Exit(65)
+	}
+	config.OrderValidator = entities.NewOrderValidator(rules)
 
 	// if env var does not exist  - set def value
 	if exist {
@@ -87,6 +301,180 @@ func InitConfig() *Config {
 		zap.S().Infoln("Env var ACCRUAL_SYSTEM_ADDRESS not found, use default", config.Accrual)
 	}
 
+	ttl, exist := os.LookupEnv(("HOLD_TTL"))
+
+	if exist {
+		if d, err := time.ParseDuration(ttl); err == nil {
+			config.HoldTTL = d
+			zap.S().Infoln("Set hold TTL from evn HOLD_TTL: ", config.HoldTTL)
+		} else {
+			zap.S().Errorln("Can't parse HOLD_TTL, use default", config.HoldTTL, err)
+		}
+	}
+
+	window, exist := os.LookupEnv(("TIER_WINDOW"))
+
+	if exist {
+		if d, err := time.ParseDuration(window); err == nil {
+			config.TierWindow = d
+			zap.S().Infoln("Set tier window from evn TIER_WINDOW: ", config.TierWindow)
+		} else {
+			zap.S().Errorln("Can't parse TIER_WINDOW, use default", config.TierWindow, err)
+		}
+	}
+
 	zap.S().Infoln("Configuration complite")
 	return &config
 }
+
+// Parse tiers from string like "bronze:0:1,silver:1000:1.25". Result sorted by threshold.
+func ParseTiers(conf string) ([]entities.Tier, error) {
+	tiers := make([]entities.Tier, 0)
+	for _, t := range strings.Split(conf, ",") {
+		parts := strings.Split(strings.TrimSpace(t), ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, errors.New("wrong tier format, use name:threshold:multiplier: " + t)
+		}
+		threshold, err := decimal.NewFromString(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		multiplier, err := decimal.NewFromString(parts[2])
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, entities.Tier{Name: parts[0], Threshold: threshold, Multiplier: multiplier})
+	}
+
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Threshold.LessThan(tiers[j].Threshold)
+	})
+
+	if len(tiers) == 0 || !tiers[0].Threshold.IsZero() {
+		return nil, errors.New("the first tier must have zero threshold")
+	}
+	return tiers, nil
+}
+
+// Parse admin keys from string like "alice:key1,bob:key2".
+func ParseAdminKeys(conf string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if strings.TrimSpace(conf) == "" {
+		return keys, nil
+	}
+	for _, k := range strings.Split(conf, ",") {
+		name, key, found := strings.Cut(strings.TrimSpace(k), ":")
+		if !found || name == "" || key == "" {
+			return nil, errors.New("wrong admin key format, use name:key")
+		}
+		keys[key] = name
+	}
+	return keys, nil
+}
+
+// Parse order rules from string like ":0:luhn,460:13:gs1". Empty prefix matches any number.
+func ParseOrderRules(conf string) ([]entities.OrderRule, error) {
+	rules := make([]entities.OrderRule, 0)
+	if strings.TrimSpace(conf) == "" {
+		return rules, nil
+	}
+	for _, r := range strings.Split(conf, ",") {
+		parts := strings.Split(strings.TrimSpace(r), ":")
+		if len(parts) != 3 || !entities.OrderSchemes[parts[2]] {
+			return nil, errors.New("wrong order rule format, use prefix:length:scheme: " + r)
+		}
+		length, err := strconv.Atoi(parts[1])
+		if err != nil || length < 0 {
+			return nil, errors.New("wrong order number length: " + r)
+		}
+		rules = append(rules, entities.OrderRule{Prefix: parts[0], Length: length, Scheme: parts[2]})
+	}
+	return rules, nil
+}
+
+// Load JWT keys from string like "2024-05:/etc/keys/new.pem,2024-04:/etc/keys/old.pub.pem".
+// The first key must be private and signs tokens, all keys verify tokens. Empty string - no keys.
+func ParseJWTKeys(conf string, readFile func(name string) ([]byte, error)) (*entities.JWTKeys, error) {
+	if strings.TrimSpace(conf) == "" {
+		return nil, nil
+	}
+	keys := &entities.JWTKeys{Verify: make(map[string]entities.VerifyKey)}
+	for i, k := range strings.Split(conf, ",") {
+		kid, path, found := strings.Cut(strings.TrimSpace(k), ":")
+		if !found || kid == "" || path == "" {
+			return nil, errors.New("wrong JWT key format, use kid:path")
+		}
+		if _, ok := keys.Verify[kid]; ok {
+			return nil, errors.New("duplicated JWT key id: " + kid)
+		}
+		data, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		method, private, public, err := parseKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+		if i == 0 {
+			if private == nil {
+				return nil, errors.New("signing JWT key must be private: " + kid)
+			}
+			keys.SignID, keys.Method, keys.Sign = kid, method, private
+		}
+		keys.Verify[kid] = entities.VerifyKey{Method: method, Key: public}
+	}
+	return keys, nil
+}
+
+// Parse RSA or Ed25519 key in PEM, private key is nil for public PEM.
+func parseKeyPEM(data []byte) (method jwt.SigningMethod, private crypto.Signer, public crypto.PublicKey, err error) {
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return jwt.SigningMethodRS256, key, key.Public(), nil
+	}
+	if key, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, nil, nil, errors.New("unsupported private key")
+		}
+		return jwt.SigningMethodEdDSA, signer, signer.Public(), nil
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return jwt.SigningMethodRS256, nil, key, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return jwt.SigningMethodEdDSA, nil, key, nil
+	}
+	return nil, nil, nil, errors.New("PEM is not RSA or Ed25519 key")
+}
+
+// Make login and password policy, passwords of denylist file are added to common passwords.
+func ParseCredentialPolicy(loginMin int, loginMax int, pattern string, passwordMin int, denylist string, readFile func(name string) ([]byte, error)) (entities.CredentialPolicy, error) {
+	policy := entities.CredentialPolicy{LoginMin: loginMin, LoginMax: loginMax, PasswordMin: passwordMin, Denylist: make(map[string]bool)}
+	if loginMax != 0 && loginMin > loginMax {
+		return policy, errors.New("min length of login is greater than max")
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return policy, fmt.Errorf("wrong login pattern: %w", err)
+		}
+		policy.LoginPattern = re
+	}
+
+	for _, p := range entities.CommonPasswords {
+		policy.Denylist[p] = true
+	}
+	if denylist == "" {
+		return policy, nil
+	}
+	data, err := readFile(denylist)
+	if err != nil {
+		return policy, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if p := strings.TrimSpace(line); p != "" {
+			policy.Denylist[strings.ToLower(p)] = true
+		}
+	}
+	return policy, nil
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"github.com/shulganew/gophermart/internal/app/config"
 	"github.com/shulganew/gophermart/internal/ports/client"
+	"github.com/shulganew/gophermart/internal/ports/notifier"
 	"github.com/shulganew/gophermart/internal/ports/storage"
 	"github.com/shulganew/gophermart/internal/services"
 )
@@ -15,17 +16,43 @@ type Application struct {
 	userSrv  *services.UserService
 	accSrv   *services.AccrualService
 	orderSrv *services.OrderService
+	holdSrv  *services.HoldService
+	tierSrv  *services.TierService
+	campSrv  *services.CampaignService
+	refSrv   *services.ReferralService
+	adjSrv   *services.AdjustmentService
+	dispSrv  *services.DisputeService
+	sessSrv  *services.SessionService
+	guard    *services.LoginGuard
+	passSrv  *services.PasswordService
+	adminSrv *services.AdminService
+	merchSrv *services.MerchantService
+	bus      *services.EventBus
+	hookSrv  *services.WebhookService
 	conf     *config.Config
 }
 
 func NewApp(conf *config.Config, stor *storage.Repo) *Application {
 	application := &Application{}
 	application.conf = conf
-	application.calcSrv = services.NewCalcService(stor)
-	application.userSrv = services.NewUserService(stor)
+	application.bus = services.NewEventBus(config.EventsBuffer)
+	application.calcSrv = services.NewCalcService(stor, conf.WithdrawPolicy, application.bus)
+	application.userSrv = services.NewUserService(stor, conf.CredentialPolicy)
+	application.sessSrv = services.NewSessionService(stor, conf.JWTKeys, conf.AccessTTL, conf.RefreshTTL)
+	application.guard = services.NewLoginGuard(stor, conf.LoginPolicy)
+	application.passSrv = services.NewPasswordService(stor, application.userSrv, notifier.NewFile(conf.NotifyFile), conf.ResetTTL)
 	application.client = client.NewAccrualClient(conf)
-	application.accSrv = services.NewAccrualService(stor, application.client)
-	application.orderSrv = services.NewOrderService(stor)
+	application.tierSrv = services.NewTierService(stor, conf.Tiers, conf.TierWindow)
+	application.campSrv = services.NewCampaignService(stor)
+	application.refSrv = services.NewReferralService(stor, conf.ReferrerBonus, conf.RefereeBonus, conf.ReferralLimit)
+	application.accSrv = services.NewAccrualService(stor, application.client, application.tierSrv, application.campSrv, application.refSrv, conf.ReturnWindow, application.bus)
+	application.orderSrv = services.NewOrderService(stor, conf.OrderValidator)
+	application.holdSrv = services.NewHoldService(stor, conf.HoldTTL, application.bus)
+	application.hookSrv = services.NewWebhookService(stor, conf.WebhookPrivate)
+	application.adjSrv = services.NewAdjustmentService(stor, conf.ApprovalThreshold)
+	application.dispSrv = services.NewDisputeService(stor, application.bus)
+	application.adminSrv = services.NewAdminService(stor)
+	application.merchSrv = services.NewMerchantService(stor)
 	application.stor = stor
 
 	return application
@@ -43,6 +70,18 @@ func (c *Application) UserService() *services.UserService {
 	return c.userSrv
 }
 
+func (c *Application) SessionService() *services.SessionService {
+	return c.sessSrv
+}
+
+func (c *Application) LoginGuard() *services.LoginGuard {
+	return c.guard
+}
+
+func (c *Application) PasswordService() *services.PasswordService {
+	return c.passSrv
+}
+
 func (c *Application) AccrualService() *services.AccrualService {
 	return c.accSrv
 }
@@ -51,6 +90,46 @@ func (c *Application) OrderService() *services.OrderService {
 	return c.orderSrv
 }
 
+func (c *Application) HoldService() *services.HoldService {
+	return c.holdSrv
+}
+
+func (c *Application) TierService() *services.TierService {
+	return c.tierSrv
+}
+
+func (c *Application) CampaignService() *services.CampaignService {
+	return c.campSrv
+}
+
+func (c *Application) ReferralService() *services.ReferralService {
+	return c.refSrv
+}
+
+func (c *Application) AdjustmentService() *services.AdjustmentService {
+	return c.adjSrv
+}
+
+func (c *Application) DisputeService() *services.DisputeService {
+	return c.dispSrv
+}
+
+func (c *Application) AdminService() *services.AdminService {
+	return c.adminSrv
+}
+
+func (c *Application) MerchantService() *services.MerchantService {
+	return c.merchSrv
+}
+
+func (c *Application) EventBus() *services.EventBus {
+	return c.bus
+}
+
+func (c *Application) WebhookService() *services.WebhookService {
+	return c.hookSrv
+}
+
 func (c *Application) Config() *config.Config {
 	return c.conf
 }
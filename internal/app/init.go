@@ -36,6 +36,14 @@ func InitApp(ctx context.Context) (application *Application, err error) {
 	accSrv := application.AccrualService()
 	accSrv.Run(ctx)
 
+	// Run expiration of stale bonuses holds.
+	holdSrv := application.HoldService()
+	holdSrv.Run(ctx)
+
+	// Run delivery of webhooks.
+	hookSrv := application.WebhookService()
+	hookSrv.Run(ctx)
+
 	zap.S().Infoln("Application init complite")
 	return application, nil
 }
@@ -1,14 +1,34 @@
 package entities
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
 
 type AddOrder struct {
 	UserID     string
 	OrderNr    string
 	IsPreOrder bool
 	Withdrawn  decimal.Decimal
+	Purchase   *Purchase
+	// Merchant's key of upload on behalf of user.
+	MerchantKey *uuid.UUID
 }
 
 func NewAddOrder(userID string, orderNr string, isPreOrder bool, withdrawn decimal.Decimal) *AddOrder {
 	return &AddOrder{UserID: userID, OrderNr: orderNr, IsPreOrder: isPreOrder, Withdrawn: withdrawn}
 }
+
+// Outcome of single order upload.
+type UploadOutcome int
+
+const (
+	// New order, 202.
+	UploadAccepted UploadOutcome = iota
+	// Order already uploaded by user, 200.
+	UploadAlreadyYours
+	// User's preorder, created with withdrawal, should be moved to regular order, 200.
+	UploadPreOrder
+	// Order uploaded by other user, 409.
+	UploadOtherUser
+)
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type AdjustmentStatus string
+
+const (
+	// Waiting for approval of the second admin.
+	AWAITING AdjustmentStatus = "PENDING"
+	APPLIED  AdjustmentStatus = "APPLIED"
+	REJECTED AdjustmentStatus = "REJECTED"
+)
+
+// Reason codes of manual balance adjustments.
+var AdjustmentReasons = map[string]bool{
+	"GOODWILL":   true,
+	"CORRECTION": true,
+	"COMPLAINT":  true,
+	"FRAUD":      true,
+	"MIGRATION":  true,
+	"OTHER":      true,
+}
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrAdjustmentNotFound = errors.New("adjustment not found")
+	ErrAdjustmentDecided  = errors.New("adjustment already decided")
+	ErrSelfApproval       = errors.New("adjustment can't be approved by its author")
+)
+
+// Request for manual adjustment, positive sum - credit, negative - debit.
+type AdjustmentRequest struct {
+	Sum    float64 `json:"sum"`
+	Reason string  `json:"reason"`
+	Note   string  `json:"note"`
+}
+
+// Manual adjustment of user's bonuses by admin.
+type Adjustment struct {
+	AdjustmentID uuid.UUID        `db:"adjustment_id"`
+	UserID       uuid.UUID        `db:"user_id"`
+	Amount       decimal.Decimal  `db:"amount"`
+	Reason       string           `db:"reason"`
+	Note         string           `db:"note"`
+	Status       AdjustmentStatus `db:"status"`
+	CreatedBy    string           `db:"created_by"`
+	DecidedBy    *string          `db:"decided_by"`
+	Created      time.Time        `db:"created"`
+	Decided      *time.Time       `db:"decided"`
+}
+
+func NewAdjustment(userID uuid.UUID, ar *AdjustmentRequest, admin string) *Adjustment {
+	return &Adjustment{UserID: userID, Amount: decimal.NewFromFloat(ar.Sum), Reason: ar.Reason, Note: ar.Note, CreatedBy: admin, Created: time.Now()}
+}
+
+// Reason code and note are mandatory.
+func (a *Adjustment) IsValid() bool {
+	return !a.Amount.IsZero() && AdjustmentReasons[a.Reason] && a.Note != ""
+}
+
+func (a *Adjustment) MarshalJSON() ([]byte, error) {
+	var decided *string
+	if a.Decided != nil {
+		t := a.Decided.Format(time.RFC3339)
+		decided = &t
+	}
+	return json.Marshal(struct {
+		ID        string  `json:"id"`
+		UserID    string  `json:"user_id"`
+		Sum       float64 `json:"sum"`
+		Reason    string  `json:"reason"`
+		Note      string  `json:"note"`
+		Status    string  `json:"status"`
+		CreatedBy string  `json:"created_by"`
+		DecidedBy *string `json:"decided_by,omitempty"`
+		Created   string  `json:"created_at"`
+		Decided   *string `json:"decided_at,omitempty"`
+	}{
+		ID:        a.AdjustmentID.String(),
+		UserID:    a.UserID.String(),
+		Sum:       a.Amount.InexactFloat64(),
+		Reason:    a.Reason,
+		Note:      a.Note,
+		Status:    string(a.Status),
+		CreatedBy: a.CreatedBy,
+		DecidedBy: a.DecidedBy,
+		Created:   a.Created.Format(time.RFC3339),
+		Decided:   decided,
+	})
+}
+
+// Record of admin's action.
+type AuditRecord struct {
+	Actor   string          `db:"actor" json:"actor"`
+	Action  string          `db:"action" json:"action"`
+	UserID  *uuid.UUID      `db:"user_id" json:"user_id,omitempty"`
+	Details json.RawMessage `db:"details" json:"details"`
+	Created time.Time       `db:"created" json:"created_at"`
+}
+
+func NewAuditRecord(actor string, action string, userID *uuid.UUID, details any) *AuditRecord {
+	d, err := json.Marshal(details)
+	if err != nil {
+		d = []byte("{}")
+	}
+	return &AuditRecord{Actor: actor, Action: action, UserID: userID, Details: d, Created: time.Now()}
+}
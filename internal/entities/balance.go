@@ -5,10 +5,17 @@ import "github.com/shopspring/decimal"
 type UserBalance struct {
 	Bonus     float64 `json:"current"`
 	Withdrawn float64 `json:"withdrawn"`
+	Held      float64 `json:"held"`
+	Debt      float64 `json:"debt,omitempty"`
 }
 
-func NewUserBalance(bonus decimal.Decimal, withdrawn decimal.Decimal) *UserBalance {
+func NewUserBalance(bonus decimal.Decimal, withdrawn decimal.Decimal, held decimal.Decimal) *UserBalance {
 	b := bonus.InexactFloat64()
 	w := withdrawn.InexactFloat64()
-	return &UserBalance{Bonus: b, Withdrawn: w}
+	h := held.InexactFloat64()
+	// Negative bonuses after returns are shown as debt.
+	if bonus.IsNegative() {
+		return &UserBalance{Bonus: 0, Withdrawn: w, Held: h, Debt: -b}
+	}
+	return &UserBalance{Bonus: b, Withdrawn: w, Held: h}
 }
@@ -0,0 +1,36 @@
+package entities
+
+import "github.com/gofrs/uuid"
+
+// Max orders and body size of one bulk upload.
+const (
+	MaxBatch      = 1000
+	MaxBatchBytes = 1 << 20
+)
+
+// Results of order's bulk upload, same as single upload answers: 202, 200, 409, 422.
+const (
+	BatchAccepted     = "accepted"
+	BatchAlreadyYours = "already_yours"
+	BatchOtherUser    = "other_user"
+	BatchInvalid      = "invalid"
+)
+
+// Order of bulk upload after insert.
+type BatchOrder struct {
+	OrderNr    string     `db:"order_number"`
+	Inserted   bool       `db:"inserted"`
+	Owner      *uuid.UUID `db:"owner"`
+	IsPreOrder bool       `db:"is_preorder"`
+}
+
+// Result of bulk upload for one order.
+type BatchResult struct {
+	OrderNr string `json:"number"`
+	Result  string `json:"result"`
+	// Reason of invalid order number.
+	Reason string `json:"reason,omitempty"`
+
+	// User's preorder, should be moved to regular order.
+	PreOrder bool `json:"-"`
+}
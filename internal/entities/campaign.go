@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Kind of promotional campaign.
+type CampaignKind string
+
+const (
+	// Extra points: order's accrual multiplied by (value - 1).
+	MULTIPLIER CampaignKind = "MULTIPLIER"
+	// Extra points: fixed value.
+	FIXED CampaignKind = "FIXED"
+)
+
+var ErrCampaignNotFound = errors.New("campaign not found")
+
+// Request for campaign creation.
+type CampaignRequest struct {
+	Name       string       `json:"name"`
+	Kind       CampaignKind `json:"kind"`
+	Value      float64      `json:"value"`
+	FirstOrder bool         `json:"first_order"`
+	MinAccrual float64      `json:"min_accrual"`
+	Stackable  bool         `json:"stackable"`
+	Starts     time.Time    `json:"starts_at"`
+	Ends       time.Time    `json:"ends_at"`
+}
+
+// Time-boxed promotional campaign, credits extra points on processed orders.
+type Campaign struct {
+	CampaignID uuid.UUID       `db:"campaign_id"`
+	Name       string          `db:"name"`
+	Kind       CampaignKind    `db:"kind"`
+	Value      decimal.Decimal `db:"value"`
+	FirstOrder bool            `db:"first_order"`
+	MinAccrual decimal.Decimal `db:"min_accrual"`
+	Stackable  bool            `db:"stackable"`
+	Starts     time.Time       `db:"starts"`
+	Ends       time.Time       `db:"ends"`
+	Paused     bool            `db:"paused"`
+	Created    time.Time       `db:"created"`
+}
+
+func NewCampaign(cr *CampaignRequest) *Campaign {
+	return &Campaign{
+		Name:       cr.Name,
+		Kind:       cr.Kind,
+		Value:      decimal.NewFromFloat(cr.Value),
+		FirstOrder: cr.FirstOrder,
+		MinAccrual: decimal.NewFromFloat(cr.MinAccrual),
+		Stackable:  cr.Stackable,
+		Starts:     cr.Starts,
+		Ends:       cr.Ends,
+		Created:    time.Now(),
+	}
+}
+
+// Check campaign's settings.
+func (c *Campaign) IsValid() bool {
+	if c.Name == "" || !c.Ends.After(c.Starts) || !c.Value.IsPositive() {
+		return false
+	}
+	return c.Kind == MULTIPLIER || c.Kind == FIXED
+}
+
+// Extra points for the order's accrual.
+func (c *Campaign) Extra(accrual decimal.Decimal) decimal.Decimal {
+	if c.Kind == MULTIPLIER {
+		return accrual.Mul(c.Value.Sub(decimal.NewFromInt(1))).Round(2)
+	}
+	return c.Value
+}
+
+func (c *Campaign) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID         string  `json:"id"`
+		Name       string  `json:"name"`
+		Kind       string  `json:"kind"`
+		Value      float64 `json:"value"`
+		FirstOrder bool    `json:"first_order"`
+		MinAccrual float64 `json:"min_accrual"`
+		Stackable  bool    `json:"stackable"`
+		Starts     string  `json:"starts_at"`
+		Ends       string  `json:"ends_at"`
+		Paused     bool    `json:"paused"`
+	}{
+		ID:         c.CampaignID.String(),
+		Name:       c.Name,
+		Kind:       string(c.Kind),
+		Value:      c.Value.InexactFloat64(),
+		FirstOrder: c.FirstOrder,
+		MinAccrual: c.MinAccrual.InexactFloat64(),
+		Stackable:  c.Stackable,
+		Starts:     c.Starts.Format(time.RFC3339),
+		Ends:       c.Ends.Format(time.RFC3339),
+		Paused:     c.Paused,
+	})
+}
+
+// Campaign's results.
+type CampaignReport struct {
+	CampaignID uuid.UUID       `db:"campaign_id"`
+	Credits    int             `db:"credits"`
+	Users      int             `db:"users"`
+	Total      decimal.Decimal `db:"total"`
+}
+
+func (r *CampaignReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID      string  `json:"id"`
+		Credits int     `json:"credits"`
+		Users   int     `json:"users"`
+		Total   float64 `json:"total"`
+	}{
+		ID:      r.CampaignID.String(),
+		Credits: r.Credits,
+		Users:   r.Users,
+		Total:   r.Total.InexactFloat64(),
+	})
+}
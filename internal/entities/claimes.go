@@ -9,4 +9,5 @@ import (
 type Claims struct {
 	jwt.RegisteredClaims
 	UserID uuid.UUID
+	Role   string `json:"role,omitempty"`
 }
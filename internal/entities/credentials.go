@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Codes of login and password validation errors.
+const (
+	LoginEmpty        = "login_empty"
+	LoginTooShort     = "login_too_short"
+	LoginTooLong      = "login_too_long"
+	LoginInvalidChars = "login_invalid_chars"
+	PasswordEmpty     = "password_empty"
+	PasswordTooShort  = "password_too_short"
+	PasswordTooLong   = "password_too_long"
+	PasswordCommon    = "password_common"
+	PasswordIsLogin   = "password_is_login"
+)
+
+// Bcrypt uses only first 72 bytes of password.
+const PasswordMaxBytes = 72
+
+// Passwords denied even without denylist file.
+var CommonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "password", "password1", "qwerty", "qwerty123",
+	"111111", "000000", "abc123", "iloveyou", "admin", "letmein", "welcome", "monkey", "dragon",
+	"football", "123123", "654321", "1q2w3e4r", "qwertyuiop", "passw0rd", "sunshine",
+}
+
+// Rejected field of request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Field-level validation errors of request.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, 0, len(e.Errors))
+	for _, f := range e.Errors {
+		codes = append(codes, f.Field+": "+f.Code)
+	}
+	return "validation failed: " + strings.Join(codes, ", ")
+}
+
+// Rules of user's login and password, zero limits are not checked.
+type CredentialPolicy struct {
+	LoginMin     int
+	LoginMax     int
+	LoginPattern *regexp.Regexp
+	PasswordMin  int
+	// Lower case denied passwords
+	Denylist map[string]bool
+}
+
+// Login in NFKC form without surrounding spaces, logins are unique ignoring case.
+func NormalizeLogin(login string) string {
+	return norm.NFKC.String(strings.TrimSpace(login))
+}
+
+// Key of login for case-insensitive lookups.
+func LoginKey(login string) string {
+	return strings.ToLower(NormalizeLogin(login))
+}
+
+// Check login and password of new user, returns normalized login.
+func (p CredentialPolicy) Validate(login string, password string) (string, error) {
+	login = NormalizeLogin(login)
+	fields := p.checkLogin(login)
+	fields = append(fields, p.checkPassword(password, login)...)
+	if len(fields) != 0 {
+		return login, &ValidationError{Errors: fields}
+	}
+	return login, nil
+}
+
+// Check new password of existing user.
+func (p CredentialPolicy) ValidatePassword(password string) error {
+	fields := p.checkPassword(password, "")
+	if len(fields) != 0 {
+		return &ValidationError{Errors: fields}
+	}
+	return nil
+}
+
+func (p CredentialPolicy) checkLogin(login string) []FieldError {
+	length := utf8.RuneCountInString(login)
+	switch {
+	case length == 0:
+		return []FieldError{{Field: "login", Code: LoginEmpty, Message: "login is required"}}
+	case p.LoginMin != 0 && length < p.LoginMin:
+		return []FieldError{{Field: "login", Code: LoginTooShort, Message: fmt.Sprintf("login must have at least %d characters", p.LoginMin)}}
+	case p.LoginMax != 0 && length > p.LoginMax:
+		return []FieldError{{Field: "login", Code: LoginTooLong, Message: fmt.Sprintf("login must have at most %d characters", p.LoginMax)}}
+	case p.LoginPattern != nil && !p.LoginPattern.MatchString(login):
+		return []FieldError{{Field: "login", Code: LoginInvalidChars, Message: "login contains not allowed characters"}}
+	}
+	return nil
+}
+
+func (p CredentialPolicy) checkPassword(password string, login string) []FieldError {
+	switch {
+	case password == "":
+		return []FieldError{{Field: "password", Code: PasswordEmpty, Message: "password is required"}}
+	case p.PasswordMin != 0 && utf8.RuneCountInString(password) < p.PasswordMin:
+		return []FieldError{{Field: "password", Code: PasswordTooShort, Message: fmt.Sprintf("password must have at least %d characters", p.PasswordMin)}}
+	case len(password) > PasswordMaxBytes:
+		return []FieldError{{Field: "password", Code: PasswordTooLong, Message: fmt.Sprintf("password must have at most %d bytes", PasswordMaxBytes)}}
+	case p.Denylist[strings.ToLower(password)]:
+		return []FieldError{{Field: "password", Code: PasswordCommon, Message: "password is too common"}}
+	case login != "" && strings.EqualFold(password, login):
+		return []FieldError{{Field: "password", Code: PasswordIsLogin, Message: "password must differ from login"}}
+	}
+	return nil
+}
@@ -2,14 +2,16 @@ package entities
 
 import "github.com/gofrs/uuid"
 
-// send pass to midleware.
-type CtxPassKey struct{}
+// Name of admin, authorized by admin key or admin's access token.
+type CtxAdminKey struct{}
 
 // Send values through middleware in context.
 
 type MiddlwDTO struct {
 	userID       uuid.UUID
 	isRegistered bool
+	sessionID    uuid.UUID
+	role         string
 }
 
 func NewMiddlwDTO(userID uuid.UUID, isRegistered bool) MiddlwDTO {
@@ -23,3 +25,27 @@ func (c MiddlwDTO) GetUserID() uuid.UUID {
 func (c MiddlwDTO) IsRegistered() bool {
 	return c.isRegistered
 }
+
+// Copy with session of access token.
+func (c MiddlwDTO) WithSession(sessionID uuid.UUID) MiddlwDTO {
+	c.sessionID = sessionID
+	return c
+}
+
+func (c MiddlwDTO) GetSessionID() uuid.UUID {
+	return c.sessionID
+}
+
+// Copy with role of access token.
+func (c MiddlwDTO) WithRole(role string) MiddlwDTO {
+	c.role = role
+	return c
+}
+
+// Role of user, tokens issued before roles have user's role.
+func (c MiddlwDTO) GetRole() string {
+	if c.role == "" {
+		return RoleUser
+	}
+	return c.role
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeOpen DisputeStatus = "OPEN"
+	// Order moved to claimant with its accrual.
+	DisputeReassigned DisputeStatus = "REASSIGNED"
+	DisputeRejected   DisputeStatus = "REJECTED"
+)
+
+var (
+	ErrDisputeNotFound = errors.New("dispute not found")
+	ErrDisputeDecided  = errors.New("dispute already decided")
+	ErrDisputeExists   = errors.New("order already disputed")
+	// Accrual of disputed order waits for decision.
+	ErrOrderDisputed = errors.New("order is disputed")
+	// Own orders and preorders paid with bonuses can't be disputed.
+	ErrNotDisputable = errors.New("order can't be disputed")
+)
+
+// User's claim for order uploaded by other user.
+type DisputeRequest struct {
+	OrderNr  string `json:"number"`
+	Evidence string `json:"evidence"`
+}
+
+// Admin's decision note.
+type DisputeDecision struct {
+	Note string `json:"note"`
+}
+
+// Ownership dispute of order, order's crediting is paused while dispute is open.
+type Dispute struct {
+	DisputeID uuid.UUID       `db:"dispute_id"`
+	OrderNr   string          `db:"order_number"`
+	Claimant  uuid.UUID       `db:"claimant"`
+	Owner     uuid.UUID       `db:"owner"`
+	Evidence  string          `db:"evidence"`
+	Status    DisputeStatus   `db:"status"`
+	Moved     decimal.Decimal `db:"moved"`
+	Note      *string         `db:"note"`
+	DecidedBy *string         `db:"decided_by"`
+	Created   time.Time       `db:"created"`
+	Decided   *time.Time      `db:"decided"`
+}
+
+func NewDispute(claimant uuid.UUID, dr *DisputeRequest) *Dispute {
+	return &Dispute{OrderNr: dr.OrderNr, Claimant: claimant, Evidence: dr.Evidence, Status: DisputeOpen, Created: time.Now()}
+}
+
+// Evidence (receipt, payment details) is mandatory.
+func (d *Dispute) IsValid() bool {
+	return d.OrderNr != "" && d.Evidence != ""
+}
+
+// Owner of disputed order is not shown to claimant.
+func (d *Dispute) MarshalJSON() ([]byte, error) {
+	var decided *string
+	if d.Decided != nil {
+		t := d.Decided.Format(time.RFC3339)
+		decided = &t
+	}
+	return json.Marshal(struct {
+		ID        string  `json:"id"`
+		Order     string  `json:"number"`
+		Claimant  string  `json:"claimant"`
+		Evidence  string  `json:"evidence"`
+		Status    string  `json:"status"`
+		Moved     float64 `json:"moved,omitempty"`
+		Note      *string `json:"note,omitempty"`
+		DecidedBy *string `json:"decided_by,omitempty"`
+		Created   string  `json:"created_at"`
+		Decided   *string `json:"decided_at,omitempty"`
+	}{
+		ID:        d.DisputeID.String(),
+		Order:     d.OrderNr,
+		Claimant:  d.Claimant.String(),
+		Evidence:  d.Evidence,
+		Status:    string(d.Status),
+		Moved:     d.Moved.InexactFloat64(),
+		Note:      d.Note,
+		DecidedBy: d.DecidedBy,
+		Created:   d.Created.Format(time.RFC3339),
+		Decided:   decided,
+	})
+}
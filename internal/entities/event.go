@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Kinds of user's events.
+const (
+	EventOrderStatus = "order.status"
+	EventBalance     = "balance"
+)
+
+// User's event, published by services to event bus.
+type Event struct {
+	ID      uint64          `json:"id"`
+	UserID  uuid.UUID       `json:"-"`
+	Kind    string          `json:"kind"`
+	Data    json.RawMessage `json:"data"`
+	Created time.Time       `json:"created_at"`
+}
+
+// Data of order's status change event.
+type OrderEvent struct {
+	OrderNr string   `json:"number"`
+	Status  Status   `json:"status"`
+	Accrual *float64 `json:"accrual,omitempty"`
+}
+
+func NewOrderEvent(orderNr string, status Status, accrual decimal.Decimal) OrderEvent {
+	event := OrderEvent{OrderNr: orderNr, Status: status}
+	if accrual.IsPositive() {
+		acc := accrual.InexactFloat64()
+		event.Accrual = &acc
+	}
+	return event
+}
+
+// Data of balance change event.
+type BalanceEvent struct {
+	Kind    EntryKind `json:"kind"`
+	Amount  float64   `json:"amount"`
+	OrderNr *string   `json:"order,omitempty"`
+}
+
+func NewWithdrawalEvent(orderNr string, amount decimal.Decimal) BalanceEvent {
+	return BalanceEvent{Kind: WITHDRAWAL, Amount: amount.Neg().InexactFloat64(), OrderNr: &orderNr}
+}
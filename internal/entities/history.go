@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sources of order's status changes.
+const (
+	SourceUpload     = "upload"
+	SourceMerchant   = "merchant"
+	SourceWithdrawal = "withdrawal"
+	SourcePreOrder   = "preorder"
+	SourceAccrual    = "accrual"
+	SourceReturn     = "return"
+	SourceDeletion   = "deletion"
+)
+
+var (
+	ErrOrderNotFound = errors.New("order not found")
+	// Only new regular orders, not disputed, can be deleted.
+	ErrOrderNotDeletable = errors.New("order can't be deleted")
+)
+
+// Order's status transition.
+type StatusChange struct {
+	Status  Status           `db:"status"`
+	Accrual *decimal.Decimal `db:"accrual"`
+	Source  string           `db:"source"`
+	Created time.Time        `db:"created"`
+}
+
+func (s *StatusChange) MarshalJSON() ([]byte, error) {
+	var accrual *float64
+	if s.Accrual != nil {
+		a := s.Accrual.InexactFloat64()
+		accrual = &a
+	}
+	return json.Marshal(struct {
+		Status  string   `json:"status"`
+		Accrual *float64 `json:"accrual,omitempty"`
+		Source  string   `json:"source"`
+		Created string   `json:"changed_at"`
+	}{
+		Status:  s.Status.String(),
+		Accrual: accrual,
+		Source:  s.Source,
+		Created: s.Created.Format(time.RFC3339),
+	})
+}
+
+// Order's current state with status history.
+type OrderDetail struct {
+	Order   *Order
+	History []StatusChange
+}
+
+func (o *OrderDetail) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Number   string         `json:"number"`
+		Status   string         `json:"status"`
+		Accrual  *float64       `json:"accrual,omitempty"`
+		Uploded  string         `json:"uploaded_at"`
+		Purchase *Purchase      `json:"purchase,omitempty"`
+		History  []StatusChange `json:"history"`
+	}{
+		Number:   o.Order.OrderNr,
+		Status:   o.Order.Status.String(),
+		Accrual:  o.Order.getAccrual(),
+		Uploded:  o.Order.getUploded(),
+		Purchase: o.Order.Purchase,
+		History:  o.History,
+	})
+}
@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type HoldStatus string
+
+const (
+	HELD     HoldStatus = "HELD"
+	CAPTURED HoldStatus = "CAPTURED"
+	VOIDED   HoldStatus = "VOIDED"
+	EXPIRED  HoldStatus = "EXPIRED"
+)
+
+var (
+	ErrHoldNotFound     = errors.New("hold not found")
+	ErrHoldNotActive    = errors.New("hold is not active")
+	ErrNotEnoughBonuses = errors.New("not enough bonuses")
+)
+
+// Request for creating hold.
+type HoldRequest struct {
+	Sum float64 `json:"sum"`
+	// Sum of the order, required if share of the order paid with bonuses is limited.
+	OrderSum float64 `json:"order_sum,omitempty"`
+}
+
+// Request for capturing hold to the order.
+type CaptureRequest struct {
+	OrderNr string `json:"order"`
+}
+
+// Reserved user's bonuses, not withdrawn yet.
+type Hold struct {
+	HoldID  uuid.UUID       `db:"hold_id"`
+	UserID  uuid.UUID       `db:"user_id"`
+	Amount  decimal.Decimal `db:"amount"`
+	Status  HoldStatus      `db:"status"`
+	OrderNr *string         `db:"order_number"`
+	Created time.Time       `db:"created"`
+	Expires time.Time       `db:"expires"`
+}
+
+func (h *Hold) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID      string  `json:"id"`
+		Sum     float64 `json:"sum"`
+		Status  string  `json:"status"`
+		Order   *string `json:"order,omitempty"`
+		Created string  `json:"created_at"`
+		Expires string  `json:"expires_at"`
+	}{
+		ID:      h.HoldID.String(),
+		Sum:     h.Amount.InexactFloat64(),
+		Status:  string(h.Status),
+		Order:   h.OrderNr,
+		Created: h.Created.Format(time.RFC3339),
+		Expires: h.Expires.Format(time.RFC3339),
+	})
+}
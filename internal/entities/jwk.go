@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Key accepted for verification of JWT with kid header.
+type VerifyKey struct {
+	Method jwt.SigningMethod
+	Key    crypto.PublicKey
+}
+
+// Keys of JWT: access tokens are signed with one key, any of verification keys is accepted.
+// Keys of previous rotation are kept for verification until issued tokens expire.
+type JWTKeys struct {
+	// Kid of signing key, empty for HMAC secret.
+	SignID string
+	Method jwt.SigningMethod
+	Sign   interface{}
+	Verify map[string]VerifyKey
+}
+
+// Keys of HMAC secret, tokens are issued without kid.
+func NewHMACKeys(pass string) *JWTKeys {
+	return &JWTKeys{
+		Method: jwt.SigningMethodHS256,
+		Sign:   []byte(pass),
+		Verify: map[string]VerifyKey{"": {Method: jwt.SigningMethodHS256, Key: []byte(pass)}},
+	}
+}
+
+// Signing methods of verification keys.
+func (k *JWTKeys) Methods() []string {
+	methods := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, v := range k.Verify {
+		if !seen[v.Method.Alg()] {
+			seen[v.Method.Alg()] = true
+			methods = append(methods, v.Method.Alg())
+		}
+	}
+	return methods
+}
+
+// Public verification keys, HMAC secret is never published.
+func (k *JWTKeys) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0)}
+	for kid, v := range k.Verify {
+		switch key := v.Key.(type) {
+		case *rsa.PublicKey:
+			e := big.NewInt(int64(key.E)).Bytes()
+			set.Keys = append(set.Keys, JWK{Kty: "RSA", Kid: kid, Use: "sig", Alg: v.Method.Alg(), N: encodeJWK(key.N.Bytes()), E: encodeJWK(e)})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{Kty: "OKP", Kid: kid, Use: "sig", Alg: v.Method.Alg(), Crv: "Ed25519", X: encodeJWK(key)})
+		}
+	}
+
+	// Signing key goes first.
+	sort.Slice(set.Keys, func(i, j int) bool {
+		if set.Keys[i].Kid == k.SignID || set.Keys[j].Kid == k.SignID {
+			return set.Keys[i].Kid == k.SignID
+		}
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set
+}
+
+// JSON Web Key Set (RFC 7517) of /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA modulus and exponent.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 curve and public key.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func encodeJWK(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Kind of user's balance movement.
+type EntryKind string
+
+const (
+	ACCRUAL    EntryKind = "ACCRUAL"
+	WITHDRAWAL EntryKind = "WITHDRAWAL"
+	HOLD       EntryKind = "HOLD"
+	RELEASE    EntryKind = "RELEASE"
+	CAMPAIGN   EntryKind = "CAMPAIGN"
+	REFERRAL   EntryKind = "REFERRAL"
+	ADJUSTMENT EntryKind = "ADJUSTMENT"
+	CLAWBACK   EntryKind = "CLAWBACK"
+	// Accrual moved between users by dispute resolution.
+	DISPUTE EntryKind = "DISPUTE"
+)
+
+// Entry of user's balance history. Positive amount - credit, negative - debit of bonuses.
+type LedgerEntry struct {
+	UserID  uuid.UUID       `db:"user_id"`
+	OrderNr *string         `db:"order_number"`
+	Kind    EntryKind       `db:"kind"`
+	Amount  decimal.Decimal `db:"amount"`
+	Label   string          `db:"label"`
+	Created time.Time       `db:"created"`
+}
+
+func NewLedgerEntry(userID uuid.UUID, orderNr *string, kind EntryKind, amount decimal.Decimal, label string) *LedgerEntry {
+	return &LedgerEntry{UserID: userID, OrderNr: orderNr, Kind: kind, Amount: amount, Label: label, Created: time.Now()}
+}
+
+func (e *LedgerEntry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind    string  `json:"kind"`
+		Order   *string `json:"order,omitempty"`
+		Sum     float64 `json:"sum"`
+		Label   string  `json:"label,omitempty"`
+		Created string  `json:"created_at"`
+	}{
+		Kind:    string(e.Kind),
+		Order:   e.OrderNr,
+		Sum:     e.Amount.InexactFloat64(),
+		Label:   e.Label,
+		Created: e.Created.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrNotLocked = errors.New("login has no failed attempts")
+
+// Failed attempts are counted per login and per client IP, password reset requests - in own scopes.
+const (
+	AttemptLogin   = "login"
+	AttemptIP      = "ip"
+	AttemptReset   = "reset"
+	AttemptResetIP = "reset_ip"
+)
+
+// Limits of failed logins. After each failure next attempt waits progressive delay,
+// after limit of failures login or IP is locked.
+type LoginPolicy struct {
+	// Delay after the first failure, doubled after each next one up to MaxDelay
+	Delay    time.Duration
+	MaxDelay time.Duration
+	// Failures before lockout, 0 - no lockout
+	LoginLimit int
+	IPLimit    int
+	Lockout    time.Duration
+}
+
+// Key of counted attempts and its limit of failures before lockout.
+type AttemptKey struct {
+	Scope string
+	Key   string
+	Limit int
+}
+
+// Attempts of login: counted per login and per IP.
+func (p LoginPolicy) LoginKeys(login string, ip string) []AttemptKey {
+	return []AttemptKey{{Scope: AttemptLogin, Key: LoginKey(login), Limit: p.LoginLimit}, {Scope: AttemptIP, Key: ip, Limit: p.IPLimit}}
+}
+
+// Password reset requests: counted per login and per IP with limits of login.
+func (p LoginPolicy) ResetKeys(login string, ip string) []AttemptKey {
+	return []AttemptKey{{Scope: AttemptReset, Key: LoginKey(login), Limit: p.LoginLimit}, {Scope: AttemptResetIP, Key: ip, Limit: p.IPLimit}}
+}
+
+// Failed attempts of login or IP.
+type LoginAttempts struct {
+	Scope       string     `db:"scope"`
+	Key         string     `db:"key"`
+	Failures    int        `db:"failures"`
+	LastFailure *time.Time `db:"last_failure"`
+	LockedUntil *time.Time `db:"locked_until"`
+}
+
+// Time to wait before attempt of all keys, 0 - attempt allowed.
+func (p LoginPolicy) Wait(attempts []LoginAttempts, now time.Time) time.Duration {
+	var wait time.Duration
+	for i := range attempts {
+		if retry := p.RetryAfter(&attempts[i], now); retry > wait {
+			wait = retry
+		}
+	}
+	return wait
+}
+
+// Time to wait before next attempt, 0 - attempt allowed.
+func (p LoginPolicy) RetryAfter(a *LoginAttempts, now time.Time) time.Duration {
+	if a.LockedUntil != nil && a.LockedUntil.After(now) {
+		return a.LockedUntil.Sub(now)
+	}
+	if a.Failures == 0 || a.LastFailure == nil || p.Delay == 0 {
+		return 0
+	}
+
+	// Without MaxDelay doubling stops before overflow of duration.
+	maxDelay := p.MaxDelay
+	if maxDelay == 0 {
+		maxDelay = math.MaxInt64 / 2
+	}
+	delay := p.Delay
+	for i := 1; i < a.Failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	if next := a.LastFailure.Add(delay); next.After(now) {
+		return next.Sub(now)
+	}
+	return 0
+}
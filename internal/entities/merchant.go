@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+// Permissions of merchant API keys.
+const (
+	ScopeOrdersWrite = "orders:write"
+	ScopeOrdersRead  = "orders:read"
+)
+
+var MerchantScopes = map[string]bool{ScopeOrdersWrite: true, ScopeOrdersRead: true}
+
+// Prefix of merchant API keys.
+const MerchantKeyPrefix = "gm_"
+
+var ErrKeyNotFound = errors.New("merchant key not found, revoked or expired")
+
+// Key of merchant, authorized by API key header.
+type CtxMerchantKey struct{}
+
+type MerchantKeyRequest struct {
+	Merchant string     `json:"merchant"`
+	Scopes   []string   `json:"scopes"`
+	Expires  *time.Time `json:"expires_at"`
+}
+
+// Order uploaded by merchant for user with login.
+type MerchantOrderRequest struct {
+	Login    string    `json:"login"`
+	OrderNr  string    `json:"number"`
+	Purchase *Purchase `json:"purchase,omitempty"`
+}
+
+// API key of merchant's server, only hash of key is stored.
+type MerchantKey struct {
+	KeyID     uuid.UUID      `db:"key_id"`
+	Merchant  string         `db:"merchant"`
+	Prefix    string         `db:"prefix"`
+	KeyHash   string         `db:"key_hash"`
+	Scopes    pq.StringArray `db:"scopes"`
+	CreatedBy string         `db:"created_by"`
+	Created   time.Time      `db:"created"`
+	Expires   *time.Time     `db:"expires"`
+	LastUsed  *time.Time     `db:"last_used"`
+	Revoked   *time.Time     `db:"revoked"`
+}
+
+// Key of merchant from admin's request.
+func NewMerchantKey(admin string, kr *MerchantKeyRequest) *MerchantKey {
+	return &MerchantKey{Merchant: kr.Merchant, Scopes: kr.Scopes, CreatedBy: admin, Created: time.Now(), Expires: kr.Expires}
+}
+
+// Merchant is set, key is not expired and has known scopes.
+func (k *MerchantKey) IsValid() bool {
+	if k.Merchant == "" || len(k.Scopes) == 0 {
+		return false
+	}
+	if k.Expires != nil && k.Expires.Before(time.Now()) {
+		return false
+	}
+	for _, scope := range k.Scopes {
+		if !MerchantScopes[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+func (k *MerchantKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor of merchant's actions.
+func (k *MerchantKey) Actor() string {
+	return "merchant:" + k.Merchant + ":" + k.KeyID.String()
+}
+
+// Hash of key is not returned.
+func (k *MerchantKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID        string     `json:"id"`
+		Merchant  string     `json:"merchant"`
+		Prefix    string     `json:"prefix"`
+		Scopes    []string   `json:"scopes"`
+		CreatedBy string     `json:"created_by"`
+		Created   string     `json:"created_at"`
+		Expires   *time.Time `json:"expires_at,omitempty"`
+		LastUsed  *time.Time `json:"last_used_at,omitempty"`
+		Revoked   *time.Time `json:"revoked_at,omitempty"`
+	}{
+		ID:        k.KeyID.String(),
+		Merchant:  k.Merchant,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedBy: k.CreatedBy,
+		Created:   k.Created.Format(time.RFC3339),
+		Expires:   k.Expires,
+		LastUsed:  k.LastUsed,
+		Revoked:   k.Revoked,
+	})
+}
+
+// Issued key, the key itself is shown only once.
+type IssuedKey struct {
+	Key         string       `json:"key"`
+	MerchantKey *MerchantKey `json:"merchant_key"`
+}
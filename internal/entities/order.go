@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/ShiraazMoollatjie/goluhn"
 	"github.com/gofrs/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -24,18 +23,15 @@ type Order struct {
 	Status     Status          `db:"status"`
 	Withdrawn  decimal.Decimal `db:"withdrawn"`
 	Accrual    decimal.Decimal `db:"accrual"`
+	Purchase   *Purchase       `db:"metadata"`
+	// Merchant's key if order is uploaded by merchant.
+	MerchantKey *uuid.UUID `db:"merchant_key_id"`
 }
 
 func NewOrder(userID uuid.UUID, orderNr string, preoreder bool, withdrawn decimal.Decimal, accrual decimal.Decimal) *Order {
 	return &Order{UserID: userID, OrderNr: orderNr, IsPreOrder: preoreder, Uploaded: time.Now(), Status: Status(NEW), Withdrawn: withdrawn, Accrual: accrual}
 }
 
-// Check Luna namber.
-func (o *Order) IsValid() (isValid bool) {
-	err := goluhn.Validate(o.OrderNr)
-	return err == nil
-}
-
 func (o *Order) getAccrual() *float64 {
 	if o.Accrual.IsZero() {
 		return nil
@@ -50,14 +46,16 @@ func (o *Order) getUploded() string {
 
 func (o *Order) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Number  string   `json:"number"`
-		Status  string   `json:"status"`
-		Accrual *float64 `json:"accrual,omitempty"`
-		Uploded string   `json:"uploaded_at"`
+		Number   string    `json:"number"`
+		Status   string    `json:"status"`
+		Accrual  *float64  `json:"accrual,omitempty"`
+		Uploded  string    `json:"uploaded_at"`
+		Purchase *Purchase `json:"purchase,omitempty"`
 	}{
-		Number:  o.OrderNr,
-		Status:  o.Status.String(),
-		Accrual: o.getAccrual(),
-		Uploded: o.getUploded(),
+		Number:   o.OrderNr,
+		Status:   o.Status.String(),
+		Accrual:  o.getAccrual(),
+		Uploded:  o.getUploded(),
+		Purchase: o.Purchase,
 	})
 }
@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+var ErrWrongFilter = errors.New("wrong filter")
+
+// Position in the list of orders: upload time and number of the last returned order.
+type OrderCursor struct {
+	Uploaded time.Time
+	OrderNr  string
+}
+
+func (c OrderCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Uploaded.Format(time.RFC3339Nano) + "|" + c.OrderNr))
+}
+
+func ParseCursor(cursor string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrWrongFilter
+	}
+	uploaded, orderNr, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, ErrWrongFilter
+	}
+	t, err := time.Parse(time.RFC3339Nano, uploaded)
+	if err != nil {
+		return nil, ErrWrongFilter
+	}
+	return &OrderCursor{Uploaded: t, OrderNr: orderNr}, nil
+}
+
+// Filter, sort and page of user's orders.
+type OrderFilter struct {
+	Status []string
+	From   *time.Time
+	To     *time.Time
+	Asc    bool
+	// Page size, 0 - all orders without pagination.
+	Limit  int
+	Cursor *OrderCursor
+}
+
+// Parse filter from query: limit, cursor, status (comma separated), from, to (RFC3339), sort (asc|desc).
+// Orders are paginated only if limit or cursor is given, cursor without limit uses DefaultPageLimit.
+func ParseOrderFilter(query url.Values) (*OrderFilter, error) {
+	filter := &OrderFilter{}
+
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 || l > MaxPageLimit {
+			return nil, ErrWrongFilter
+		}
+		filter.Limit = l
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		c, err := ParseCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = c
+		if filter.Limit == 0 {
+			filter.Limit = DefaultPageLimit
+		}
+	}
+
+	if status := query.Get("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			switch Status(strings.ToUpper(s)) {
+			case NEW, PROCESSING, INVALID, PROCESSED, REGISTERED:
+				filter.Status = append(filter.Status, strings.ToUpper(s))
+			default:
+				return nil, ErrWrongFilter
+			}
+		}
+	}
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, ErrWrongFilter
+		}
+		filter.From = &t
+	}
+
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, ErrWrongFilter
+		}
+		filter.To = &t
+	}
+
+	switch query.Get("sort") {
+	case "", "desc":
+	case "asc":
+		filter.Asc = true
+	default:
+		return nil, ErrWrongFilter
+	}
+
+	return filter, nil
+}
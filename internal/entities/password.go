@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	ErrWrongPassword = errors.New("wrong password")
+	ErrResetNotValid = errors.New("reset token not found, used or expired")
+)
+
+// Change of password by logged in user.
+type PasswordChange struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// Request of reset token, token is sent to user by notifier.
+type ResetRequest struct {
+	Login string `json:"login"`
+}
+
+// New password set with reset token.
+type ResetConfirm struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// Single-use password reset, only hash of token is stored.
+type PasswordReset struct {
+	TokenHash string     `db:"token_hash"`
+	UserID    uuid.UUID  `db:"user_id"`
+	Created   time.Time  `db:"created"`
+	Expires   time.Time  `db:"expires"`
+	Used      *time.Time `db:"used"`
+}
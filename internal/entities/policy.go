@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Machine-readable codes of withdrawal policy violations.
+const (
+	WithdrawBelowMin     = "withdraw_below_min"
+	WithdrawAboveMax     = "withdraw_above_max"
+	DailyLimitExceeded   = "daily_limit_exceeded"
+	MonthlyLimitExceeded = "monthly_limit_exceeded"
+	OrderShareExceeded   = "order_share_exceeded"
+	OrderSumRequired     = "order_sum_required"
+	RegistrationCooldown = "registration_cooldown"
+)
+
+// Withdrawal limits, zero value disables the limit.
+type WithdrawPolicy struct {
+	Min     decimal.Decimal
+	Max     decimal.Decimal
+	Daily   decimal.Decimal
+	Monthly decimal.Decimal
+	// Max share of the order's sum, which can be paid with bonuses.
+	Share decimal.Decimal
+	// Time after registration without withdrawals.
+	Cooldown time.Duration
+}
+
+// Daily and monthly limits from the start of current day and month.
+// Checked by storage in the same transaction as debit of bonuses.
+type WithdrawLimits struct {
+	Daily   decimal.Decimal
+	Monthly decimal.Decimal
+	Day     time.Time
+	Month   time.Time
+}
+
+func (p WithdrawPolicy) Limits(now time.Time) WithdrawLimits {
+	return WithdrawLimits{
+		Daily:   p.Daily,
+		Monthly: p.Monthly,
+		Day:     time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		Month:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+	}
+}
+
+// Check withdrawals of current day and month, including new amount.
+func (l WithdrawLimits) Check(daily, monthly decimal.Decimal) error {
+	if !l.Daily.IsZero() && daily.GreaterThan(l.Daily) {
+		return NewPolicyError(DailyLimitExceeded, "daily withdrawals limit is "+l.Daily.String())
+	}
+	if !l.Monthly.IsZero() && monthly.GreaterThan(l.Monthly) {
+		return NewPolicyError(MonthlyLimitExceeded, "monthly withdrawals limit is "+l.Monthly.String())
+	}
+	return nil
+}
+
+// Withdrawal policy violation.
+type PolicyError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewPolicyError(code string, message string) *PolicyError {
+	return &PolicyError{Code: code, Message: message}
+}
+
+func (e *PolicyError) Error() string {
+	return e.Code + ": " + e.Message
+}
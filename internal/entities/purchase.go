@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Line of purchase, same as goods of accrual system.
+type Good struct {
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+}
+
+// Purchase metadata of order.
+type Purchase struct {
+	StoreID     string     `json:"store_id,omitempty"`
+	Amount      float64    `json:"amount,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	PurchasedAt *time.Time `json:"purchased_at,omitempty"`
+	Goods       []Good     `json:"goods,omitempty"`
+}
+
+// JSON variant of order upload.
+type OrderRequest struct {
+	OrderNr string `json:"number"`
+	Purchase
+}
+
+// Currency is ISO 4217 code, amount and prices are not negative.
+func (p *Purchase) IsValid() bool {
+	if p.Amount < 0 || (p.Currency != "" && !currencyCode.MatchString(p.Currency)) {
+		return false
+	}
+	for _, good := range p.Goods {
+		if good.Price < 0 || good.Description == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Stored as jsonb.
+func (p Purchase) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *Purchase) Scan(src any) error {
+	raw, ok := src.([]byte)
+	if !ok {
+		return errors.New("purchase metadata must be json")
+	}
+	return json.Unmarshal(raw, p)
+}
@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ReferralStatus string
+
+const (
+	PENDING  ReferralStatus = "PENDING"
+	REWARDED ReferralStatus = "REWARDED"
+	// Referee rewarded, referrer reached the rewards cap.
+	CAPPED ReferralStatus = "CAPPED"
+)
+
+var ErrReferralNotFound = errors.New("referral code not found")
+
+// Referrer invited referee by referral code.
+type Referral struct {
+	ReferrerID    uuid.UUID       `db:"referrer_id"`
+	RefereeID     uuid.UUID       `db:"referee_id"`
+	RefereeLogin  string          `db:"login"`
+	Status        ReferralStatus  `db:"status"`
+	ReferrerBonus decimal.Decimal `db:"referrer_bonus"`
+	RefereeBonus  decimal.Decimal `db:"referee_bonus"`
+	Created       time.Time       `db:"created"`
+	Rewarded      *time.Time      `db:"rewarded"`
+}
+
+func (r *Referral) MarshalJSON() ([]byte, error) {
+	var rewarded *string
+	if r.Rewarded != nil {
+		t := r.Rewarded.Format(time.RFC3339)
+		rewarded = &t
+	}
+	return json.Marshal(struct {
+		Login    string  `json:"login"`
+		Status   string  `json:"status"`
+		Bonus    float64 `json:"bonus"`
+		Created  string  `json:"created_at"`
+		Rewarded *string `json:"rewarded_at,omitempty"`
+	}{
+		Login:    r.RefereeLogin,
+		Status:   string(r.Status),
+		Bonus:    r.ReferrerBonus.InexactFloat64(),
+		Created:  r.Created.Format(time.RFC3339),
+		Rewarded: rewarded,
+	})
+}
+
+// User's referral code, invited users and earned rewards.
+type ReferralsInfo struct {
+	Code      string     `json:"code"`
+	Earned    float64    `json:"earned"`
+	Referrals []Referral `json:"referrals"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Roles of users, role is set to access token and checked by middleware.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var Roles = map[string]bool{RoleUser: true, RoleAdmin: true}
+
+var ErrWrongRole = errors.New("unknown role")
+
+type RoleRequest struct {
+	Role string `json:"role"`
+}
+
+// User's account for admin.
+type UserInfo struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Login      string    `db:"login" json:"login"`
+	Role       string    `db:"role" json:"role"`
+	Registered time.Time `db:"registered" json:"registered_at"`
+	Tier       string    `db:"tier" json:"tier"`
+}
+
+// Order of any user with history for admin.
+type OrderInspection struct {
+	Detail OrderDetail
+}
+
+func (o *OrderInspection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		UserID      uuid.UUID       `json:"user_id"`
+		PreOrder    bool            `json:"preorder"`
+		Withdrawn   decimal.Decimal `json:"withdrawn"`
+		MerchantKey *uuid.UUID      `json:"merchant_key_id,omitempty"`
+		Order       *OrderDetail    `json:"order"`
+	}{
+		UserID:      o.Detail.Order.UserID,
+		PreOrder:    o.Detail.Order.IsPreOrder,
+		Withdrawn:   o.Detail.Order.Withdrawn,
+		MerchantKey: o.Detail.Order.MerchantKey,
+		Order:       &o.Detail,
+	})
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session revoked or expired")
+	// Refresh token used twice, session is revoked.
+	ErrRefreshReused = errors.New("refresh token reused")
+)
+
+// User's login session, access tokens carry session id as jti.
+// Only hash of refresh token is stored, token is rotated on every refresh.
+type Session struct {
+	SessionID   uuid.UUID  `db:"session_id"`
+	UserID      uuid.UUID  `db:"user_id"`
+	Role        string     `db:"role"`
+	RefreshHash string     `db:"refresh_hash"`
+	Created     time.Time  `db:"created"`
+	Expires     time.Time  `db:"expires"`
+	Revoked     *time.Time `db:"revoked"`
+}
+
+// Tokens issued on login and refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// Access token lifetime, seconds.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
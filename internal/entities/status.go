@@ -8,6 +8,8 @@ const (
 	INVALID    Status = "INVALID"
 	PROCESSED  Status = "PROCESSED"
 	REGISTERED Status = "REGISTERED"
+	// Upload withdrawn by user, only in order's history.
+	DELETED Status = "DELETED"
 )
 
 func (s *Status) String() string {
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Loyalty tier, reached by user's accruals in rolling window.
+type Tier struct {
+	Name       string
+	Threshold  decimal.Decimal
+	Multiplier decimal.Decimal
+}
+
+// Change of user's tier.
+type TierChange struct {
+	UserID   uuid.UUID       `db:"user_id"`
+	From     string          `db:"tier_from"`
+	To       string          `db:"tier_to"`
+	Lifetime decimal.Decimal `db:"lifetime"`
+	Changed  time.Time       `db:"changed"`
+}
+
+func (c *TierChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		From     string  `json:"from"`
+		To       string  `json:"to"`
+		Lifetime float64 `json:"lifetime"`
+		Changed  string  `json:"changed_at"`
+	}{
+		From:     c.From,
+		To:       c.To,
+		Lifetime: c.Lifetime.InexactFloat64(),
+		Changed:  c.Changed.Format(time.RFC3339),
+	})
+}
+
+// User's loyalty profile.
+type Profile struct {
+	Tier       string       `json:"tier"`
+	Multiplier float64      `json:"multiplier"`
+	Lifetime   float64      `json:"lifetime"`
+	NextTier   string       `json:"next_tier,omitempty"`
+	ToNextTier float64      `json:"to_next_tier,omitempty"`
+	History    []TierChange `json:"history"`
+}
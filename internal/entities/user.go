@@ -12,4 +12,7 @@ type User struct {
 	PassHash    string          `db:"password_hash"`
 	Withdrawals decimal.Decimal `db:"withdrawals"`
 	Bonuses     decimal.Decimal `db:"bonuses"`
+
+	// Referral code of inviting user, optional during registration.
+	Referrer string `json:"referral_code,omitempty"`
 }
@@ -0,0 +1,149 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Check-digit schemes of order numbers.
+const (
+	SchemeLuhn  = "luhn"
+	SchemeGS1   = "gs1"
+	SchemeMod11 = "mod11"
+	SchemeNone  = "none"
+)
+
+var OrderSchemes = map[string]bool{
+	SchemeLuhn:  true,
+	SchemeGS1:   true,
+	SchemeMod11: true,
+	SchemeNone:  true,
+}
+
+// Reasons of order number rejection.
+const (
+	OrderEmpty        = "order_empty"
+	OrderNotDigits    = "order_not_digits"
+	OrderWrongLength  = "order_wrong_length"
+	OrderCheckDigit   = "order_check_digit"
+	OrderUnknownStore = "order_unknown_store"
+)
+
+// Rejected order number with reason code.
+type OrderError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewOrderError(code string, message string) *OrderError {
+	return &OrderError{Code: code, Message: message}
+}
+
+func (e *OrderError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Format of order numbers of partner store, store is found by number's prefix.
+type OrderRule struct {
+	Prefix string
+	// Exact length of number with prefix, 0 - any length.
+	Length int
+	Scheme string
+}
+
+func (r *OrderRule) Validate(orderNr string) error {
+	if r.Length != 0 && len(orderNr) != r.Length {
+		return NewOrderError(OrderWrongLength, fmt.Sprintf("order number must have %d digits", r.Length))
+	}
+	if !checkDigit(r.Scheme, orderNr) {
+		return NewOrderError(OrderCheckDigit, "wrong "+r.Scheme+" check digit")
+	}
+	return nil
+}
+
+// Registry of stores' rules. Rule with the longest matching prefix is used,
+// empty registry checks Luhn number of any length.
+type OrderValidator struct {
+	rules []OrderRule
+}
+
+func NewOrderValidator(rules []OrderRule) OrderValidator {
+	sorted := append([]OrderRule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Prefix) > len(sorted[j].Prefix)
+	})
+	return OrderValidator{rules: sorted}
+}
+
+// Nil if order number is valid, otherwise *OrderError with reason.
+func (v OrderValidator) Validate(orderNr string) error {
+	if orderNr == "" {
+		return NewOrderError(OrderEmpty, "order number is empty")
+	}
+	for _, c := range orderNr {
+		if c < '0' || c > '9' {
+			return NewOrderError(OrderNotDigits, "order number must contain only digits")
+		}
+	}
+	if len(v.rules) == 0 {
+		rule := OrderRule{Scheme: SchemeLuhn}
+		return rule.Validate(orderNr)
+	}
+	for _, rule := range v.rules {
+		if strings.HasPrefix(orderNr, rule.Prefix) {
+			return rule.Validate(orderNr)
+		}
+	}
+	return NewOrderError(OrderUnknownStore, "no store for order number prefix")
+}
+
+// Check the last digit of number, number contains only digits.
+func checkDigit(scheme string, number string) bool {
+	digits := make([]int, len(number))
+	for i, c := range number {
+		digits[i] = int(c - '0')
+	}
+	last := len(digits) - 1
+	if last < 1 {
+		return scheme == SchemeNone
+	}
+
+	switch scheme {
+	case SchemeNone:
+		return true
+	case SchemeLuhn:
+		sum := 0
+		for i := last; i >= 0; i-- {
+			d := digits[i]
+			if (last-i)%2 == 1 {
+				d *= 2
+				if d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		return sum%10 == 0
+	case SchemeGS1:
+		// EAN/GTIN: weights 3 and 1 from the right, check digit excluded.
+		sum := 0
+		for i := last - 1; i >= 0; i-- {
+			weight := 1
+			if (last-1-i)%2 == 0 {
+				weight = 3
+			}
+			sum += digits[i] * weight
+		}
+		return (10-sum%10)%10 == digits[last]
+	case SchemeMod11:
+		// Weights 2..7 from the right, remainder 10 is not allowed.
+		sum := 0
+		for i := last - 1; i >= 0; i-- {
+			sum += digits[i] * (2 + (last-1-i)%6)
+		}
+		check := (11 - sum%11) % 11
+		return check != 10 && check == digits[last]
+	}
+	return false
+}
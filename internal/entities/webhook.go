@@ -0,0 +1,187 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+)
+
+// Webhook events.
+const (
+	WebhookOrderProcessed  = "order.processed"
+	WebhookOrderInvalid    = "order.invalid"
+	WebhookWithdrawCreated = "withdrawal.created"
+)
+
+// Headers of webhook request.
+const (
+	WebhookSignatureHeader = "X-Gophermart-Signature"
+	WebhookEventHeader     = "X-Gophermart-Event"
+	WebhookDeliveryHeader  = "X-Gophermart-Delivery"
+	WebhookTimestampHeader = "X-Gophermart-Timestamp"
+)
+
+const minWebhookSecret = 16
+
+var WebhookEvents = map[string]bool{
+	WebhookOrderProcessed:  true,
+	WebhookOrderInvalid:    true,
+	WebhookWithdrawCreated: true,
+}
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// Webhooks are not sent to loopback, private and link-local addresses.
+	ErrWebhookTarget = errors.New("webhook target address is not public")
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryFailed    DeliveryStatus = "FAILED"
+)
+
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// User's webhook, secret is used for HMAC signature of deliveries.
+type Webhook struct {
+	WebhookID uuid.UUID      `db:"webhook_id"`
+	UserID    uuid.UUID      `db:"user_id"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Events    pq.StringArray `db:"events"`
+	Created   time.Time      `db:"created"`
+}
+
+// Webhook from request, all events if events are not set.
+func NewWebhook(userID uuid.UUID, wr *WebhookRequest) *Webhook {
+	events := wr.Events
+	if len(events) == 0 {
+		events = []string{WebhookOrderProcessed, WebhookOrderInvalid, WebhookWithdrawCreated}
+	}
+	return &Webhook{UserID: userID, URL: wr.URL, Secret: wr.Secret, Events: events, Created: time.Now()}
+}
+
+// Absolute http(s) URL, long enough secret and known events.
+// Host must not be local or not public address, unless private targets are allowed.
+func (w *Webhook) IsValid(allowPrivate bool) bool {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	if !allowPrivate {
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return false
+		}
+		if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+			return false
+		}
+	}
+	if len(w.Secret) < minWebhookSecret {
+		return false
+	}
+	for _, event := range w.Events {
+		if !WebhookEvents[event] {
+			return false
+		}
+	}
+	return true
+}
+
+// Address is not loopback, private, link-local, multicast or unspecified.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// Secret is not returned.
+func (w *Webhook) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID      string   `json:"id"`
+		URL     string   `json:"url"`
+		Events  []string `json:"events"`
+		Created string   `json:"created_at"`
+	}{
+		ID:      w.WebhookID.String(),
+		URL:     w.URL,
+		Events:  w.Events,
+		Created: w.Created.Format(time.RFC3339),
+	})
+}
+
+// Delivery of event to webhook.
+type Delivery struct {
+	DeliveryID   uuid.UUID       `db:"delivery_id"`
+	WebhookID    uuid.UUID       `db:"webhook_id"`
+	Event        string          `db:"event"`
+	Payload      json.RawMessage `db:"payload"`
+	Status       DeliveryStatus  `db:"status"`
+	Attempts     int             `db:"attempts"`
+	NextAttempt  time.Time       `db:"next_attempt"`
+	ResponseCode *int            `db:"response_code"`
+	Error        string          `db:"error"`
+	Created      time.Time       `db:"created"`
+	Delivered    *time.Time      `db:"delivered"`
+
+	// Target of delivery, loaded with due deliveries.
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+func (d *Delivery) MarshalJSON() ([]byte, error) {
+	var delivered *string
+	if d.Delivered != nil {
+		t := d.Delivered.Format(time.RFC3339)
+		delivered = &t
+	}
+	return json.Marshal(struct {
+		ID           string  `json:"id"`
+		Event        string  `json:"event"`
+		Status       string  `json:"status"`
+		Attempts     int     `json:"attempts"`
+		ResponseCode *int    `json:"response_code,omitempty"`
+		Error        string  `json:"error,omitempty"`
+		Created      string  `json:"created_at"`
+		Delivered    *string `json:"delivered_at,omitempty"`
+	}{
+		ID:           d.DeliveryID.String(),
+		Event:        d.Event,
+		Status:       string(d.Status),
+		Attempts:     d.Attempts,
+		ResponseCode: d.ResponseCode,
+		Error:        d.Error,
+		Created:      d.Created.Format(time.RFC3339),
+		Delivered:    delivered,
+	})
+}
+
+// Webhook event of order's status, empty if status is not sent to webhooks.
+func WebhookOrderEvent(status Status) string {
+	switch status {
+	case PROCESSED:
+		return WebhookOrderProcessed
+	case INVALID:
+		return WebhookOrderInvalid
+	}
+	return ""
+}
+
+// Body of webhook request.
+type WebhookPayload struct {
+	Event   string          `json:"event"`
+	Created time.Time       `json:"created_at"`
+	Data    json.RawMessage `json:"data"`
+}
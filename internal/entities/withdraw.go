@@ -7,6 +7,8 @@ import (
 type Withdraw struct {
 	OrderNr   string  `json:"order"`
 	Withdrawn float64 `json:"sum"`
+	// Sum of the order, required if share of the order paid with bonuses is limited.
+	OrderSum float64 `json:"order_sum,omitempty"`
 }
 
 type Withdrawals struct {
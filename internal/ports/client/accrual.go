@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -57,3 +58,35 @@ func (a Accrual) GetOrderStatus(orderNr string) (*entities.AccrualResponce, erro
 
 	return &accResp, nil
 }
+
+// Register order with goods in Accrual system.
+func (a Accrual) RegisterOrder(orderNr string, goods []entities.Good) error {
+	client := &http.Client{}
+
+	url, err := url.JoinPath(a.conf.Accrual, "api", "orders")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(struct {
+		Order string          `json:"order"`
+		Goods []entities.Good `json:"goods"`
+	}{Order: orderNr, Goods: goods})
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	// 409 - order alredy registered
+	if res.StatusCode != http.StatusAccepted && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusConflict {
+		return errors.New("no correct answer from accural system: " + strconv.Itoa(res.StatusCode))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notifier for local development: messages are appended to file as JSON lines
+// or written to log if file is not set.
+type File struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+type resetMessage struct {
+	Login   string    `json:"login"`
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires_at"`
+	Sent    time.Time `json:"sent_at"`
+}
+
+func (f *File) SendPasswordReset(ctx context.Context, login string, token string, expires time.Time) error {
+	msg := resetMessage{Login: login, Token: token, Expires: expires, Sent: time.Now()}
+	if f.path == "" {
+		zap.S().Infoln("Password reset token for: ", login, token, " expires ", expires)
+		return nil
+	}
+
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("can't open notifications file: %w", err)
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("can't write notification: %w", err)
+	}
+	return nil
+}
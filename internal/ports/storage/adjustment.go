@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Add adjustment, if its status is APPLIED - change user's bonuses in the same transaction.
+func (r *Repo) AddAdjustment(ctx context.Context, adj *entities.Adjustment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for adjustment: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	INSERT INTO adjustments (user_id, amount, reason, note, status, created_by, decided_by, created, decided)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING adjustment_id
+	`
+	err = tx.GetContext(ctx, &adj.AdjustmentID, query, adj.UserID, adj.Amount, adj.Reason, adj.Note, adj.Status,
+		adj.CreatedBy, adj.DecidedBy, adj.Created, adj.Decided)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgerrcode.ForeignKeyViolation == pgErr.Code {
+			return entities.ErrUserNotFound
+		}
+		return fmt.Errorf("can't add adjustment: %w", err)
+	}
+
+	if adj.Status == entities.APPLIED {
+		err = applyAdjustment(ctx, tx, adj)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(adj.CreatedBy, "adjustment.created", &adj.UserID, adj))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during adjustment: %w", err)
+	}
+	return nil
+}
+
+// Approve or reject pending adjustment. Author of adjustment can't approve it.
+func (r *Repo) DecideAdjustment(ctx context.Context, adjustmentID uuid.UUID, admin string, approve bool) (*entities.Adjustment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for adjustment decision: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT adjustment_id, user_id, amount, reason, note, status, created_by, decided_by, created, decided
+	FROM adjustments
+	WHERE adjustment_id = $1
+	FOR UPDATE
+	`
+	adj := entities.Adjustment{}
+	err = tx.GetContext(ctx, &adj, query, adjustmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrAdjustmentNotFound
+		}
+		return nil, fmt.Errorf("can't get adjustment: %w", err)
+	}
+	if adj.Status != entities.AWAITING {
+		return nil, entities.ErrAdjustmentDecided
+	}
+	if approve && adj.CreatedBy == admin {
+		return nil, entities.ErrSelfApproval
+	}
+
+	now := time.Now()
+	adj.DecidedBy = &admin
+	adj.Decided = &now
+	adj.Status = entities.REJECTED
+	action := "adjustment.rejected"
+	if approve {
+		adj.Status = entities.APPLIED
+		action = "adjustment.approved"
+		err = applyAdjustment(ctx, tx, &adj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE adjustments SET status = $1, decided_by = $2, decided = $3 WHERE adjustment_id = $4",
+		adj.Status, admin, now, adjustmentID)
+	if err != nil {
+		return nil, fmt.Errorf("can't update adjustment: %w", err)
+	}
+
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(admin, action, &adj.UserID, &adj))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during adjustment decision: %w", err)
+	}
+	return &adj, nil
+}
+
+// Adjustments with status, all if status is empty.
+func (r *Repo) GetAdjustments(ctx context.Context, status entities.AdjustmentStatus) ([]entities.Adjustment, error) {
+	query := `
+	SELECT adjustment_id, user_id, amount, reason, note, status, created_by, decided_by, created, decided
+	FROM adjustments
+	WHERE $1 = '' OR status::TEXT = $1
+	ORDER BY created DESC
+	`
+	adjustments := []entities.Adjustment{}
+	err := r.db.SelectContext(ctx, &adjustments, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("can't get adjustments: %w", err)
+	}
+	return adjustments, nil
+}
+
+// Audit trail of admins' actions, for one user if userID not nil.
+func (r *Repo) GetAudit(ctx context.Context, userID *uuid.UUID) ([]entities.AuditRecord, error) {
+	query := `
+	SELECT actor, action, user_id, details, created
+	FROM audit_log
+	WHERE $1::UUID IS NULL OR user_id = $1
+	ORDER BY created DESC, id DESC
+	`
+	audit := []entities.AuditRecord{}
+	err := r.db.SelectContext(ctx, &audit, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get audit log: %w", err)
+	}
+	return audit, nil
+}
+
+// Change user's bonuses by adjustment amount and add it to balance history.
+// Debit can't make bonuses negative.
+func applyAdjustment(ctx context.Context, tx *sqlx.Tx, adj *entities.Adjustment) error {
+	query := `
+	UPDATE users
+	SET bonuses = bonuses + $1
+	WHERE user_id = $2 AND bonuses + $1 >= 0
+	`
+	result, err := tx.ExecContext(ctx, query, adj.Amount, adj.UserID)
+	if err != nil {
+		return fmt.Errorf("can't apply adjustment to user's bonuses: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't apply adjustment to user's bonuses: %w", err)
+	}
+	if rows == 0 {
+		return entities.ErrNotEnoughBonuses
+	}
+
+	return insertEntry(ctx, tx, entities.NewLedgerEntry(adj.UserID, nil, entities.ADJUSTMENT, adj.Amount, adj.Reason))
+}
+
+// Add record to audit log.
+func insertAudit(ctx context.Context, tx sqlx.ExecerContext, record *entities.AuditRecord) error {
+	query := `
+	INSERT INTO audit_log (actor, action, user_id, details, created)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := tx.ExecContext(ctx, query, record.Actor, record.Action, record.UserID, []byte(record.Details), record.Created)
+	if err != nil {
+		return fmt.Errorf("can't add record to audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+func (r *Repo) GetUser(ctx context.Context, userID uuid.UUID) (*entities.UserInfo, error) {
+	query := `
+	SELECT user_id, login, role, registered, tier
+	FROM users
+	WHERE user_id = $1
+	`
+	user := entities.UserInfo{}
+	err := r.db.GetContext(ctx, &user, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("can't get user: %w", err)
+	}
+	return &user, nil
+}
+
+// Find user by login, case is ignored.
+func (r *Repo) FindUser(ctx context.Context, login string) (*entities.UserInfo, error) {
+	query := `
+	SELECT user_id, login, role, registered, tier
+	FROM users
+	WHERE lower(login) = lower($1)
+	`
+	user := entities.UserInfo{}
+	err := r.db.GetContext(ctx, &user, query, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("can't find user: %w", err)
+	}
+	return &user, nil
+}
+
+// Change user's role. Sessions of user are revoked, so tokens with previous role are not accepted.
+func (r *Repo) SetRole(ctx context.Context, admin string, userID uuid.UUID, role string) (*entities.UserInfo, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for role change: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT user_id, login, role, registered, tier
+	FROM users
+	WHERE user_id = $1
+	FOR UPDATE
+	`
+	user := entities.UserInfo{}
+	err = tx.GetContext(ctx, &user, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("can't get user: %w", err)
+	}
+	if user.Role == role {
+		return &user, nil
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET role = $1 WHERE user_id = $2", role, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't update user's role: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE sessions SET revoked = $1 WHERE user_id = $2 AND revoked IS NULL", time.Now(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't revoke user's sessions: %w", err)
+	}
+
+	details := map[string]any{"from": user.Role, "to": role}
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(admin, "user.role", &userID, details))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during role change: %w", err)
+	}
+	user.Role = role
+	return &user, nil
+}
+
+// Order of any user with full status history, preorders included.
+func (r *Repo) InspectOrder(ctx context.Context, order string) (*entities.OrderDetail, error) {
+	query := `
+	SELECT user_id, order_number, is_preorder, uploaded, status, withdrawn, accrual, metadata, merchant_key_id
+	FROM orders
+	WHERE order_number = $1
+	`
+	stored := entities.Order{}
+	err := r.db.GetContext(ctx, &stored, query, order)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("can't get order: %w", err)
+	}
+
+	queryHistory := `
+	SELECT status, accrual, source, created
+	FROM order_status_history
+	WHERE order_number = $1
+	ORDER BY created, id
+	`
+	history := []entities.StatusChange{}
+	err = r.db.SelectContext(ctx, &history, queryHistory, order)
+	if err != nil {
+		return nil, fmt.Errorf("can't get order's status history: %w", err)
+	}
+	return &entities.OrderDetail{Order: &stored, History: history}, nil
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+func (r *Repo) AddCampaign(ctx context.Context, campaign *entities.Campaign) error {
+	query := `
+	INSERT INTO campaigns (name, kind, value, first_order, min_accrual, stackable, starts, ends, created)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING campaign_id
+	`
+	err := r.db.GetContext(ctx, &campaign.CampaignID, query, campaign.Name, campaign.Kind, campaign.Value,
+		campaign.FirstOrder, campaign.MinAccrual, campaign.Stackable, campaign.Starts, campaign.Ends, campaign.Created)
+	if err != nil {
+		return fmt.Errorf("can't add campaign: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) GetCampaigns(ctx context.Context) ([]entities.Campaign, error) {
+	query := `
+	SELECT campaign_id, name, kind, value, first_order, min_accrual, stackable, starts, ends, paused, created
+	FROM campaigns
+	ORDER BY created DESC
+	`
+	campaigns := []entities.Campaign{}
+	err := r.db.SelectContext(ctx, &campaigns, query)
+	if err != nil {
+		return nil, fmt.Errorf("can't get campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// Load not paused campaigns with validity window including time.
+func (r *Repo) GetActiveCampaigns(ctx context.Context, at time.Time) ([]entities.Campaign, error) {
+	query := `
+	SELECT campaign_id, name, kind, value, first_order, min_accrual, stackable, starts, ends, paused, created
+	FROM campaigns
+	WHERE paused = FALSE AND starts <= $1 AND ends > $1
+	`
+	campaigns := []entities.Campaign{}
+	err := r.db.SelectContext(ctx, &campaigns, query, at)
+	if err != nil {
+		return nil, fmt.Errorf("can't get active campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+func (r *Repo) SetCampaignPaused(ctx context.Context, campaignID uuid.UUID, paused bool) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE campaigns SET paused = $1 WHERE campaign_id = $2", paused, campaignID)
+	if err != nil {
+		return fmt.Errorf("can't update campaign: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't update campaign: %w", err)
+	}
+	if rows == 0 {
+		return entities.ErrCampaignNotFound
+	}
+	return nil
+}
+
+func (r *Repo) GetCampaignReport(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignReport, error) {
+	var exist int
+	err := r.db.GetContext(ctx, &exist, "SELECT count(*) FROM campaigns WHERE campaign_id = $1", campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get campaign: %w", err)
+	}
+	if exist == 0 {
+		return nil, entities.ErrCampaignNotFound
+	}
+
+	query := `
+	SELECT $1::UUID AS campaign_id, count(*) AS credits, count(DISTINCT user_id) AS users, COALESCE(SUM(amount), 0) AS total
+	FROM campaign_credits
+	WHERE campaign_id = $1
+	`
+	report := entities.CampaignReport{}
+	err = r.db.GetContext(ctx, &report, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get campaign report: %w", err)
+	}
+	return &report, nil
+}
+
+// Count user's processed orders.
+func (r *Repo) CountProcessed(ctx context.Context, userID uuid.UUID) (processed int, err error) {
+	query := `
+	SELECT count(*)
+	FROM orders
+	WHERE user_id = $1 AND status = 'PROCESSED' AND is_preorder = FALSE
+	`
+	err = r.db.GetContext(ctx, &processed, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("can't count user's processed orders: %w", err)
+	}
+	return
+}
+
+// Credit campaign's extra points to user's bonuses and balance history.
+func (r *Repo) CreditCampaign(ctx context.Context, campaignID uuid.UUID, entry *entities.LedgerEntry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for campaign credit: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	INSERT INTO campaign_credits (campaign_id, user_id, order_number, amount, created)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.ExecContext(ctx, query, campaignID, entry.UserID, entry.OrderNr, entry.Amount, entry.Created)
+	if err != nil {
+		return fmt.Errorf("can't add campaign credit: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET bonuses = bonuses + $1 WHERE user_id = $2", entry.Amount, entry.UserID)
+	if err != nil {
+		return fmt.Errorf("can't add campaign credit to user's bonuses: %w", err)
+	}
+
+	err = insertEntry(ctx, tx, entry)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during campaign credit: %w", err)
+	}
+	return nil
+}
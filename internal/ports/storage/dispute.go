@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Open dispute and pause order's crediting.
+func (r *Repo) AddDispute(ctx context.Context, dispute *entities.Dispute) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for dispute: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var order entities.Order
+	err = tx.GetContext(ctx, &order, "SELECT user_id, order_number, is_preorder FROM orders WHERE order_number = $1 FOR UPDATE", dispute.OrderNr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entities.ErrOrderNotFound
+		}
+		return fmt.Errorf("can't get disputed order: %w", err)
+	}
+	if order.UserID == dispute.Claimant || order.IsPreOrder {
+		return entities.ErrNotDisputable
+	}
+	dispute.Owner = order.UserID
+
+	query := `
+	INSERT INTO disputes (order_number, claimant, owner, evidence, status, created)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING dispute_id
+	`
+	err = tx.GetContext(ctx, &dispute.DisputeID, query, dispute.OrderNr, dispute.Claimant, dispute.Owner, dispute.Evidence, dispute.Status, dispute.Created)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
+			return entities.ErrDisputeExists
+		}
+		return fmt.Errorf("can't add dispute: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE orders SET disputed = TRUE WHERE order_number = $1", dispute.OrderNr)
+	if err != nil {
+		return fmt.Errorf("can't mark order as disputed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during dispute: %w", err)
+	}
+	return nil
+}
+
+// Reassign disputed order to claimant with points credited for it or reject the claim.
+// Order's crediting is resumed in both cases.
+func (r *Repo) DecideDispute(ctx context.Context, disputeID uuid.UUID, admin string, reassign bool, note string) (*entities.Dispute, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for dispute decision: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT dispute_id, order_number, claimant, owner, evidence, status, moved, note, decided_by, created, decided
+	FROM disputes
+	WHERE dispute_id = $1
+	FOR UPDATE
+	`
+	dispute := entities.Dispute{}
+	err = tx.GetContext(ctx, &dispute, query, disputeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrDisputeNotFound
+		}
+		return nil, fmt.Errorf("can't get dispute: %w", err)
+	}
+	if dispute.Status != entities.DisputeOpen {
+		return nil, entities.ErrDisputeDecided
+	}
+
+	now := time.Now()
+	dispute.DecidedBy = &admin
+	dispute.Decided = &now
+	dispute.Note = &note
+	dispute.Status = entities.DisputeRejected
+	action := "dispute.rejected"
+	if reassign {
+		dispute.Status = entities.DisputeReassigned
+		action = "dispute.reassigned"
+		dispute.Moved, err = reassignOrder(ctx, tx, &dispute)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE orders SET disputed = FALSE WHERE order_number = $1", dispute.OrderNr)
+	if err != nil {
+		return nil, fmt.Errorf("can't resume disputed order: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE disputes SET status = $1, moved = $2, note = $3, decided_by = $4, decided = $5 WHERE dispute_id = $6",
+		dispute.Status, dispute.Moved, note, admin, now, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("can't update dispute: %w", err)
+	}
+
+	details := map[string]any{"dispute": &dispute, "owner": dispute.Owner}
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(admin, action, &dispute.Claimant, details))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during dispute decision: %w", err)
+	}
+	return &dispute, nil
+}
+
+// Disputes of claimant or all disputes if claimant is nil, filter by status if not empty.
+func (r *Repo) GetDisputes(ctx context.Context, claimant *uuid.UUID, status entities.DisputeStatus) ([]entities.Dispute, error) {
+	query := `
+	SELECT dispute_id, order_number, claimant, owner, evidence, status, moved, note, decided_by, created, decided
+	FROM disputes
+	WHERE ($1::UUID IS NULL OR claimant = $1) AND ($2 = '' OR status::TEXT = $2)
+	ORDER BY created DESC
+	`
+	disputes := []entities.Dispute{}
+	err := r.db.SelectContext(ctx, &disputes, query, claimant, status)
+	if err != nil {
+		return nil, fmt.Errorf("can't get disputes: %w", err)
+	}
+	return disputes, nil
+}
+
+// Move order to claimant. Points credited to owner for the order are debited from owner,
+// owner's bonuses may become negative as with clawback, and credited to claimant.
+func reassignOrder(ctx context.Context, tx *sqlx.Tx, dispute *entities.Dispute) (moved decimal.Decimal, err error) {
+	queryCredited := `
+	SELECT COALESCE(SUM(amount), 0)
+	FROM ledger
+	WHERE order_number = $1 AND user_id = $2 AND kind IN ('ACCRUAL', 'CAMPAIGN', 'CLAWBACK', 'DISPUTE')
+	`
+	err = tx.GetContext(ctx, &moved, queryCredited, dispute.OrderNr, dispute.Owner)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't get disputed order's credited points: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE orders SET user_id = $1 WHERE order_number = $2", dispute.Claimant, dispute.OrderNr)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't reassign disputed order: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE order_status_history SET user_id = $1 WHERE order_number = $2 AND user_id = $3", dispute.Claimant, dispute.OrderNr, dispute.Owner)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't reassign disputed order's history: %w", err)
+	}
+
+	if !moved.IsPositive() {
+		return decimal.Zero, nil
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET bonuses = bonuses - $1 WHERE user_id = $2", moved, dispute.Owner)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't debit owner's bonuses during reassignment: %w", err)
+	}
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(dispute.Owner, &dispute.OrderNr, entities.DISPUTE, moved.Neg(), "reassigned"))
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET bonuses = bonuses + $1 WHERE user_id = $2", moved, dispute.Claimant)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't credit claimant's bonuses during reassignment: %w", err)
+	}
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(dispute.Claimant, &dispute.OrderNr, entities.DISPUTE, moved, "reassigned"))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return moved, nil
+}
@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Reserve user's bonuses: move amount from bonuses to held and create hold.
+// Return *entities.PolicyError if reservation exceeds withdrawal limits.
+func (r *Repo) CreateHold(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, expires time.Time, limits entities.WithdrawLimits) (*entities.Hold, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for hold: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = checkLimits(ctx, tx, userID, amount, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	queryReserve := `
+	UPDATE users
+	SET bonuses = bonuses - $1, held = held + $1
+	WHERE user_id = $2 AND bonuses >= $1
+	`
+	result, err := tx.ExecContext(ctx, queryReserve, amount, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't reserve user's bonuses: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("can't reserve user's bonuses: %w", err)
+	}
+	if rows == 0 {
+		return nil, entities.ErrNotEnoughBonuses
+	}
+
+	queryHold := `
+	INSERT INTO holds (user_id, amount, created, expires)
+	VALUES ($1, $2, $3, $4)
+	RETURNING hold_id, user_id, amount, status, order_number, created, expires
+	`
+	hold := entities.Hold{}
+	err = tx.GetContext(ctx, &hold, queryHold, userID, amount, time.Now(), expires)
+	if err != nil {
+		return nil, fmt.Errorf("can't create hold: %w", err)
+	}
+
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(userID, nil, entities.HOLD, amount.Neg(), hold.HoldID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during creating hold: %w", err)
+	}
+	return &hold, nil
+}
+
+func (r *Repo) GetHolds(ctx context.Context, userID uuid.UUID) ([]entities.Hold, error) {
+	query := `
+	SELECT hold_id, user_id, amount, status, order_number, created, expires
+	FROM holds
+	WHERE user_id = $1
+	ORDER BY created DESC
+	`
+	holds := []entities.Hold{}
+	err := r.db.SelectContext(ctx, &holds, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+func (r *Repo) GetHeld(ctx context.Context, userID uuid.UUID) (held decimal.Decimal, err error) {
+	query := `
+	SELECT held
+	FROM users
+	WHERE user_id = $1
+	`
+	err = r.db.GetContext(ctx, &held, query, userID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return
+}
+
+// Capture hold: create preorder with hold's amount as withdrawn and move amount from held to withdrawals.
+func (r *Repo) CaptureHold(ctx context.Context, userID uuid.UUID, holdID uuid.UUID, order string) (*entities.Hold, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for hold capture: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	hold, err := lockActiveHold(ctx, tx, userID, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOrder := `
+	INSERT INTO orders (user_id, order_number, is_preorder, uploaded, withdrawn)
+	VALUES ($1, $2, TRUE, $3, $4)
+	`
+	_, err = tx.ExecContext(ctx, queryOrder, userID, order, time.Now(), hold.Amount)
+	if err != nil {
+		var pgErr *pq.Error
+		// if order exist in DataBase
+		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
+			return nil, pgErr
+		}
+		return nil, fmt.Errorf("can't create preorder for hold: %w", err)
+	}
+	err = insertStatus(ctx, tx, order, entities.NEW, nil, entities.SourceWithdrawal)
+	if err != nil {
+		return nil, err
+	}
+
+	queryHold := `
+	UPDATE holds
+	SET status = 'CAPTURED', order_number = $1
+	WHERE hold_id = $2
+	`
+	_, err = tx.ExecContext(ctx, queryHold, order, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("can't capture hold: %w", err)
+	}
+
+	queryUser := `
+	UPDATE users
+	SET held = held - $1, withdrawals = withdrawals + $1
+	WHERE user_id = $2
+	`
+	_, err = tx.ExecContext(ctx, queryUser, hold.Amount, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't move held bonuses to withdrawals: %w", err)
+	}
+
+	// Held amount released and withdrawn against the order.
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(userID, nil, entities.RELEASE, hold.Amount, holdID.String()))
+	if err != nil {
+		return nil, err
+	}
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(userID, &order, entities.WITHDRAWAL, hold.Amount.Neg(), holdID.String()))
+	if err != nil {
+		return nil, err
+	}
+	err = insertDeliveries(ctx, tx, userID, entities.WebhookWithdrawCreated, entities.NewWithdrawalEvent(order, hold.Amount))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during hold capture: %w", err)
+	}
+
+	hold.Status = entities.CAPTURED
+	hold.OrderNr = &order
+	return hold, nil
+}
+
+// Void hold: return held amount to user's bonuses.
+func (r *Repo) VoidHold(ctx context.Context, userID uuid.UUID, holdID uuid.UUID) (*entities.Hold, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for hold void: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	hold, err := lockActiveHold(ctx, tx, userID, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE holds SET status = 'VOIDED' WHERE hold_id = $1", holdID)
+	if err != nil {
+		return nil, fmt.Errorf("can't void hold: %w", err)
+	}
+
+	queryUser := `
+	UPDATE users
+	SET held = held - $1, bonuses = bonuses + $1
+	WHERE user_id = $2
+	`
+	_, err = tx.ExecContext(ctx, queryUser, hold.Amount, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't return held bonuses: %w", err)
+	}
+
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(userID, nil, entities.RELEASE, hold.Amount, holdID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during hold void: %w", err)
+	}
+
+	hold.Status = entities.VOIDED
+	return hold, nil
+}
+
+// Expire all stale holds and return held amounts to users' bonuses.
+func (r *Repo) ExpireHolds(ctx context.Context, now time.Time) (expired int, err error) {
+	query := `
+	WITH expired AS (
+		UPDATE holds
+		SET status = 'EXPIRED'
+		WHERE status = 'HELD' AND expires <= $1
+		RETURNING hold_id, user_id, amount
+	), history AS (
+		INSERT INTO ledger (user_id, kind, amount, label, created)
+		SELECT user_id, 'RELEASE', amount, hold_id::TEXT, $1
+		FROM expired
+	), totals AS (
+		SELECT user_id, SUM(amount) AS amount, COUNT(*) AS holds
+		FROM expired
+		GROUP BY user_id
+	), released AS (
+		UPDATE users
+		SET held = users.held - totals.amount, bonuses = users.bonuses + totals.amount
+		FROM totals
+		WHERE users.user_id = totals.user_id
+	)
+	SELECT COALESCE(SUM(holds), 0) FROM totals
+	`
+	err = r.db.GetContext(ctx, &expired, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("can't expire holds: %w", err)
+	}
+	return
+}
+
+// Lock user's hold in transaction and check it still active.
+func lockActiveHold(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, holdID uuid.UUID) (*entities.Hold, error) {
+	query := `
+	SELECT hold_id, user_id, amount, status, order_number, created, expires
+	FROM holds
+	WHERE hold_id = $1 AND user_id = $2
+	FOR UPDATE
+	`
+	hold := entities.Hold{}
+	err := tx.GetContext(ctx, &hold, query, holdID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("can't load hold: %w", err)
+	}
+	if hold.Status != entities.HELD || !hold.Expires.After(time.Now()) {
+		return nil, entities.ErrHoldNotActive
+	}
+	return &hold, nil
+}
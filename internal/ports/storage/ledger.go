@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Add entry to user's balance history.
+func insertEntry(ctx context.Context, tx sqlx.ExecerContext, entry *entities.LedgerEntry) error {
+	query := `
+	INSERT INTO ledger (user_id, order_number, kind, amount, label, created)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.ExecContext(ctx, query, entry.UserID, entry.OrderNr, entry.Kind, entry.Amount, entry.Label, entry.Created)
+	if err != nil {
+		return fmt.Errorf("can't add entry to user's balance history: %w", err)
+	}
+	return nil
+}
+
+// Sum of user's accruals since time, returned orders reduce it, disputed orders count for the new owner.
+func (r *Repo) GetLifetimeAccruals(ctx context.Context, userID uuid.UUID, since time.Time) (lifetime decimal.Decimal, err error) {
+	query := `
+	SELECT COALESCE(SUM(amount), 0)
+	FROM ledger
+	WHERE user_id = $1 AND kind IN ('ACCRUAL', 'CLAWBACK', 'DISPUTE') AND created >= $2
+	`
+	err = r.db.GetContext(ctx, &lifetime, query, userID, since)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't get user's lifetime accruals: %w", err)
+	}
+	return
+}
+
+// User's balance history.
+func (r *Repo) GetHistory(ctx context.Context, userID uuid.UUID) ([]entities.LedgerEntry, error) {
+	query := `
+	SELECT user_id, order_number, kind, amount, label, created
+	FROM ledger
+	WHERE user_id = $1
+	ORDER BY created DESC, id DESC
+	`
+	history := []entities.LedgerEntry{}
+	err := r.db.SelectContext(ctx, &history, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get user's balance history: %w", err)
+	}
+	return history, nil
+}
+
+// Lock user's row and check daily and monthly limits with new withdrawal amount.
+// Concurrent debits of user wait for the lock, so they see each other's withdrawals.
+func checkLimits(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal, limits entities.WithdrawLimits) error {
+	_, err := tx.ExecContext(ctx, "SELECT user_id FROM users WHERE user_id = $1 FOR UPDATE", userID)
+	if err != nil {
+		return fmt.Errorf("can't lock user for withdrawal: %w", err)
+	}
+
+	var daily, monthly decimal.Decimal
+	if !limits.Daily.IsZero() {
+		daily, err = withdrawnSince(ctx, tx, userID, limits.Day)
+		if err != nil {
+			return err
+		}
+	}
+	if !limits.Monthly.IsZero() {
+		monthly, err = withdrawnSince(ctx, tx, userID, limits.Month)
+		if err != nil {
+			return err
+		}
+	}
+	return limits.Check(daily.Add(amount), monthly.Add(amount))
+}
+
+// Sum of user's withdrawals since time, active holds are counted as withdrawals.
+func withdrawnSince(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, since time.Time) (withdrawn decimal.Decimal, err error) {
+	query := `
+	SELECT COALESCE((
+		SELECT -SUM(amount)
+		FROM ledger
+		WHERE user_id = $1 AND kind = 'WITHDRAWAL' AND created >= $2
+	), 0) + COALESCE((
+		SELECT SUM(amount)
+		FROM holds
+		WHERE user_id = $1 AND status = 'HELD'
+	), 0)
+	`
+	err = tx.GetContext(ctx, &withdrawn, query, userID, since)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't get user's withdrawals since %v: %w", since, err)
+	}
+	return
+}
+
+// User's balance restored from history up to time.
+// Hold moves bonuses to held and release moves them back, so held is the negative sum of them.
+func (r *Repo) GetBalanceAt(ctx context.Context, userID uuid.UUID, at time.Time) (bonuses, withdrawn, held decimal.Decimal, err error) {
+	query := `
+	SELECT COALESCE(SUM(l.amount), 0) AS bonuses,
+		COALESCE(-SUM(l.amount) FILTER (WHERE l.kind = 'WITHDRAWAL'), 0) AS withdrawn,
+		COALESCE(-SUM(l.amount) FILTER (WHERE l.kind IN ('HOLD', 'RELEASE')), 0) AS held
+	FROM users u
+	LEFT JOIN ledger l ON l.user_id = u.user_id AND l.created <= $2
+	WHERE u.user_id = $1
+	GROUP BY u.user_id
+	`
+	row := r.db.QueryRowContext(ctx, query, userID, at)
+	err = row.Scan(&bonuses, &withdrawn, &held)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return decimal.Zero, decimal.Zero, decimal.Zero, entities.ErrUserNotFound
+		}
+		return decimal.Zero, decimal.Zero, decimal.Zero, fmt.Errorf("can't get user's balance at %v: %w", at, err)
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Check and count attempt of all keys before password is verified. Rows of keys are locked,
+// so parallel attempts wait for each other and see counted failures. Attempt is counted as failure
+// until success resets it, attempt which must wait is not counted.
+func (r *Repo) TakeAttempt(ctx context.Context, keys []entities.AttemptKey, policy entities.LoginPolicy) (wait time.Duration, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("can't start transaction for login attempt: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	queryLock := `
+	SELECT scope, key, failures, last_failure, locked_until
+	FROM login_attempts
+	WHERE scope = $1 AND key = $2
+	FOR UPDATE
+	`
+	attempts := make([]entities.LoginAttempts, len(keys))
+	for i, key := range keys {
+		_, err = tx.ExecContext(ctx, "INSERT INTO login_attempts (scope, key) VALUES ($1, $2) ON CONFLICT (scope, key) DO NOTHING", key.Scope, key.Key)
+		if err != nil {
+			return 0, fmt.Errorf("can't add login attempts: %w", err)
+		}
+		err = tx.GetContext(ctx, &attempts[i], queryLock, key.Scope, key.Key)
+		if err != nil {
+			return 0, fmt.Errorf("can't lock login attempts: %w", err)
+		}
+	}
+
+	wait = policy.Wait(attempts, time.Now())
+	if wait > 0 {
+		return wait, nil
+	}
+
+	// After limit of failures login or IP is locked and counter starts again.
+	queryCount := `
+	UPDATE login_attempts
+	SET failures = CASE WHEN $3 > 0 AND failures + 1 >= $3 THEN 0 ELSE failures + 1 END,
+		locked_until = CASE WHEN $3 > 0 AND failures + 1 >= $3 THEN now() + $4 * interval '1 second' ELSE locked_until END,
+		last_failure = now()
+	WHERE scope = $1 AND key = $2
+	`
+	for _, key := range keys {
+		_, err = tx.ExecContext(ctx, queryCount, key.Scope, key.Key, key.Limit, policy.Lockout.Seconds())
+		if err != nil {
+			return 0, fmt.Errorf("can't count login attempt: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("cat't commit transaction during login attempt: %w", err)
+	}
+	return 0, nil
+}
+
+// Forget failed attempts after successful login.
+func (r *Repo) ResetAttempts(ctx context.Context, scope string, key string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE scope = $1 AND key = $2", scope, key)
+	if err != nil {
+		return fmt.Errorf("can't reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// Don't count successful attempt as failure.
+func (r *Repo) RefundAttempt(ctx context.Context, scope string, key string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE login_attempts SET failures = GREATEST(failures - 1, 0) WHERE scope = $1 AND key = $2", scope, key)
+	if err != nil {
+		return fmt.Errorf("can't refund login attempt: %w", err)
+	}
+	return nil
+}
+
+// Remove lockout and failed attempts of login by admin.
+func (r *Repo) UnlockLogin(ctx context.Context, admin string, login string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for login unlock: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	attempts := entities.LoginAttempts{}
+	query := `
+	DELETE FROM login_attempts
+	WHERE scope = $1 AND key = $2
+	RETURNING scope, key, failures, last_failure, locked_until
+	`
+	err = tx.GetContext(ctx, &attempts, query, entities.AttemptLogin, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entities.ErrNotLocked
+		}
+		return fmt.Errorf("can't unlock login: %w", err)
+	}
+
+	details := map[string]any{"login": login, "failures": attempts.Failures, "locked_until": attempts.LockedUntil}
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(admin, "login.unlocked", nil, details))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during login unlock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+func (r *Repo) AddMerchantKey(ctx context.Context, key *entities.MerchantKey) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for merchant key: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	INSERT INTO merchant_keys (merchant, prefix, key_hash, scopes, created_by, created, expires)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING key_id
+	`
+	err = tx.GetContext(ctx, &key.KeyID, query, key.Merchant, key.Prefix, key.KeyHash, key.Scopes, key.CreatedBy, key.Created, key.Expires)
+	if err != nil {
+		return fmt.Errorf("can't add merchant key: %w", err)
+	}
+
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(key.CreatedBy, "merchant_key.created", nil, key))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during merchant key creation: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) GetMerchantKeys(ctx context.Context) ([]entities.MerchantKey, error) {
+	query := `
+	SELECT key_id, merchant, prefix, key_hash, scopes, created_by, created, expires, last_used, revoked
+	FROM merchant_keys
+	ORDER BY created DESC
+	`
+	keys := []entities.MerchantKey{}
+	err := r.db.SelectContext(ctx, &keys, query)
+	if err != nil {
+		return nil, fmt.Errorf("can't get merchant keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *Repo) RevokeMerchantKey(ctx context.Context, admin string, keyID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for merchant key revocation: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	UPDATE merchant_keys
+	SET revoked = $1
+	WHERE key_id = $2 AND revoked IS NULL
+	RETURNING key_id, merchant, prefix, key_hash, scopes, created_by, created, expires, last_used, revoked
+	`
+	key := entities.MerchantKey{}
+	err = tx.GetContext(ctx, &key, query, time.Now(), keyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entities.ErrKeyNotFound
+		}
+		return fmt.Errorf("can't revoke merchant key: %w", err)
+	}
+
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(admin, "merchant_key.revoked", nil, &key))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during merchant key revocation: %w", err)
+	}
+	return nil
+}
+
+// Order uploaded by the key, read of order is added to audit with key as actor.
+func (r *Repo) GetMerchantOrder(ctx context.Context, key *entities.MerchantKey, userID uuid.UUID, order string) (*entities.Order, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for merchant's order: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT user_id, order_number, uploaded, status, withdrawn, accrual, metadata
+	FROM orders
+	WHERE is_preorder = FALSE AND user_id = $1 AND order_number = $2 AND merchant_key_id = $3
+	`
+	stored := entities.Order{}
+	err = tx.GetContext(ctx, &stored, query, userID, order, key.KeyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("can't get merchant's order: %w", err)
+	}
+
+	err = insertAudit(ctx, tx, entities.NewAuditRecord(key.Actor(), "merchant_order.read", &userID, map[string]string{"order": order}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during merchant's order read: %w", err)
+	}
+	return &stored, nil
+}
+
+// Active key by hash, time of use is saved.
+func (r *Repo) UseMerchantKey(ctx context.Context, keyHash string) (*entities.MerchantKey, error) {
+	query := `
+	UPDATE merchant_keys
+	SET last_used = now()
+	WHERE key_hash = $1 AND revoked IS NULL AND (expires IS NULL OR expires > now())
+	RETURNING key_id, merchant, prefix, key_hash, scopes, created_by, created, expires, last_used, revoked
+	`
+	key := entities.MerchantKey{}
+	err := r.db.GetContext(ctx, &key, query, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("can't get merchant key: %w", err)
+	}
+	return &key, nil
+}
@@ -2,46 +2,116 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/gofrs/uuid"
-	"github.com/jackc/pgerrcode"
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"github.com/shulganew/gophermart/internal/entities"
 )
 
-func (r *Repo) AddOrder(ctx context.Context, data *entities.AddOrder) error {
+// Insert order or load owner of existed one in one statement.
+// No-op update on conflict locks and returns the latest version of existed row,
+// so concurrent uploads of the same number always see the winner.
+func (r *Repo) AddOrder(ctx context.Context, data *entities.AddOrder) (*entities.BatchOrder, error) {
 	query := `
-	INSERT INTO orders (user_id, order_number, is_preorder, uploaded, withdrawn) 
-	VALUES ($1, $2, $3, $4, $5)
+	WITH o AS (
+		INSERT INTO orders (user_id, order_number, is_preorder, uploaded, withdrawn, metadata, merchant_key_id) 
+		VALUES ($1, $2, $3, $4, $5, $7, $8)
+		ON CONFLICT (order_number) DO UPDATE SET is_preorder = orders.is_preorder
+		RETURNING order_number, status, uploaded, user_id, is_preorder, xmax = 0 AS inserted
+	), hist AS (
+		INSERT INTO order_status_history (order_number, user_id, status, source, created)
+		SELECT order_number, user_id, status, $6, uploaded FROM o WHERE inserted
+	)
+	SELECT order_number, inserted, user_id AS owner, is_preorder FROM o
 	`
-	_, err := r.db.ExecContext(ctx, query, data.UserID, data.OrderNr, data.IsPreOrder, time.Now(), data.Withdrawn)
+	source := entities.SourceUpload
+	switch {
+	case data.IsPreOrder:
+		source = entities.SourceWithdrawal
+	case data.MerchantKey != nil:
+		source = entities.SourceMerchant
+	}
+	var order entities.BatchOrder
+	err := r.db.GetContext(ctx, &order, query, data.UserID, data.OrderNr, data.IsPreOrder, time.Now(), data.Withdrawn, source, data.Purchase, data.MerchantKey)
 	if err != nil {
-		var pgErr *pq.Error
-		// if order exist in DataBase
-		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
-			return pgErr
-		}
-		return fmt.Errorf("error during set order to Storage, error: %w", err)
+		return nil, fmt.Errorf("error during set order to Storage, error: %w", err)
 	}
 
-	return nil
+	return &order, nil
+}
+
+// Insert new orders of user, existed orders are skipped. Return inserted flag and owner for every order.
+// No-op update on conflict returns the latest version of existed rows, as in AddOrder.
+func (r *Repo) AddOrders(ctx context.Context, userID uuid.UUID, orders []string) ([]entities.BatchOrder, error) {
+	query := `
+	WITH input AS (
+		SELECT DISTINCT unnest($2::TEXT[]) AS order_number
+	), ins AS (
+		INSERT INTO orders (user_id, order_number, is_preorder, uploaded, withdrawn)
+		SELECT $1, order_number, FALSE, $3, 0 FROM input
+		ON CONFLICT (order_number) DO UPDATE SET is_preorder = orders.is_preorder
+		RETURNING order_number, status, uploaded, user_id, is_preorder, xmax = 0 AS inserted
+	), hist AS (
+		INSERT INTO order_status_history (order_number, user_id, status, source, created)
+		SELECT order_number, user_id, status, $4, uploaded FROM ins WHERE inserted
+	)
+	SELECT order_number, inserted, user_id AS owner, is_preorder FROM ins
+	`
+	batch := []entities.BatchOrder{}
+	err := r.db.SelectContext(ctx, &batch, query, userID, pq.Array(orders), time.Now(), entities.SourceUpload)
+	if err != nil {
+		return nil, fmt.Errorf("error during bulk upload of orders: %w", err)
+	}
+	return batch, nil
 }
 
-func (r *Repo) GetOrders(ctx context.Context, userID uuid.UUID) ([]entities.Order, error) {
+// Page of user's orders, keyset pagination by upload time and order number.
+func (r *Repo) GetOrders(ctx context.Context, userID uuid.UUID, filter *entities.OrderFilter) ([]entities.Order, error) {
 	query := `
-	SELECT user_id, order_number, uploaded, status, withdrawn, accrual
+	SELECT user_id, order_number, uploaded, status, withdrawn, accrual, metadata
 	FROM orders 
 	WHERE is_preorder = FALSE AND user_id = $1
-	ORDER BY uploaded DESC
 	`
+	args := []any{userID}
+
+	if len(filter.Status) != 0 {
+		args = append(args, pq.Array(filter.Status))
+		query += fmt.Sprintf(" AND status::TEXT = ANY($%d)", len(args))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += fmt.Sprintf(" AND uploaded >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += fmt.Sprintf(" AND uploaded < $%d", len(args))
+	}
+
+	order, compare := "DESC", "<"
+	if filter.Asc {
+		order, compare = "ASC", ">"
+	}
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.Uploaded, filter.Cursor.OrderNr)
+		query += fmt.Sprintf(" AND (uploaded, order_number) %s ($%d, $%d)", compare, len(args)-1, len(args))
+	}
+
+	query += fmt.Sprintf(" ORDER BY uploaded %s, order_number %s", order, order)
+	if filter.Limit != 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
 	orders := []entities.Order{}
-	err := r.db.MustBegin().SelectContext(ctx, &orders, query, userID)
+	err := r.db.SelectContext(ctx, &orders, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("can't get user's orders: %w", err)
 	}
 	return orders, nil
 }
@@ -60,20 +130,6 @@ func (r *Repo) IsExist(ctx context.Context, order string) (isExist bool, err err
 	return ordersn != 0, nil
 }
 
-func (r *Repo) IsExistForOtherUser(ctx context.Context, userID uuid.UUID, order string) (isExist bool, err error) {
-	query := `
-	SELECT count(*) 
-	FROM orders WHERE user_id != $1 
-	AND order_number = $2
-	`
-	var ordersn int
-	err = r.db.GetContext(ctx, &ordersn, query, userID, order)
-	if err != nil {
-		return true, fmt.Errorf("error during order search for user: %w", err)
-	}
-	return ordersn != 0, nil
-}
-
 func (r *Repo) GetWithdrawals(ctx context.Context, userID uuid.UUID) (withdrawn decimal.Decimal, err error) {
 	query := `
 	SELECT withdrawals 
@@ -101,33 +157,43 @@ func (r *Repo) Withdrawals(ctx context.Context, userID uuid.UUID) (wds []entitie
 	return
 }
 
-func (r *Repo) IsPreOrder(ctx context.Context, userID uuid.UUID, order string) (bool, error) {
-	query := `
-	SELECT count(order_number)
-	FROM orders
-	WHERE user_id = $1 AND order_number = $2 AND is_preorder = TRUE
-	`
-	var is int
-	err := r.db.GetContext(ctx, &is, query, userID, order)
-	if err != nil {
-		return true, fmt.Errorf("error during is preorder checking: %w", err)
-	}
-
-	return is != 0, err
-}
-
 // Move preorder to regular order. Add accruals for this order.
 func (r *Repo) MovePreOrder(ctx context.Context, order *entities.Order) (err error) {
 	query := `
 	UPDATE orders 
 	SET status = $1, is_preorder = $2 
-	WHERE order_number = $3
+	WHERE order_number = $3 AND is_preorder = TRUE
 	`
-	_, err = r.db.ExecContext(ctx, query, order.Status, order.IsPreOrder, order.OrderNr)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for preorder move: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.ExecContext(ctx, query, order.Status, order.IsPreOrder, order.OrderNr)
 	if err != nil {
 		return fmt.Errorf("move order error, can't move preoreder to order, %w", err)
 	}
 
+	// Preorder moved by concurrent upload.
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("move order error, can't get moved rows, %w", err)
+	}
+	if moved == 0 {
+		return nil
+	}
+
+	err = insertStatus(ctx, tx, order.OrderNr, order.Status, nil, entities.SourcePreOrder)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during preorder move: %w", err)
+	}
 	return
 }
 
@@ -139,6 +205,9 @@ func (r *Repo) GetBonuses(ctx context.Context, userID uuid.UUID) (accrual decima
 	`
 	err = r.db.GetContext(ctx, &accrual, query, userID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return decimal.Zero, entities.ErrUserNotFound
+		}
 		return decimal.Zero, err
 	}
 	return
@@ -170,28 +239,68 @@ func (r *Repo) SetAccrual(ctx context.Context, order string, accrual decimal.Dec
 	return
 }
 
-func (r *Repo) AddBonuses(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (err error) {
+// Add amount of entry to user's bonuses and record it to balance history.
+func (r *Repo) AddBonuses(ctx context.Context, entry *entities.LedgerEntry) (err error) {
 	query := `
 	UPDATE users 
 	SET bonuses = bonuses + $1 
 	WHERE user_id = $2
 	`
-	_, err = r.db.ExecContext(ctx, query, amount, userID)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for user's bonuses update: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, query, entry.Amount, entry.UserID)
 	if err != nil {
 		return fmt.Errorf("can't update add to user's order accruals to bonuses %w", err)
 	}
 
+	err = insertEntry(ctx, tx, entry)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during user's bonuses update: %w", err)
+	}
 	return
 }
 
 // Move user's amount from bonuses to withdrawals.
-func (r *Repo) MakeWithdrawn(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (err error) {
+// Return *entities.PolicyError if withdrawal exceeds limits, preorder of rejected withdrawal is removed,
+// so the order number can be used again.
+func (r *Repo) MakeWithdrawn(ctx context.Context, userID uuid.UUID, order string, amount decimal.Decimal, limits entities.WithdrawLimits) (err error) {
 	queryBonusUpdate := `
 	UPDATE users 
 	SET bonuses = bonuses - $1 
 	WHERE user_id = $2
 	`
 	tx := r.db.MustBegin()
+	err = checkLimits(ctx, tx, userID, amount, limits)
+	if err != nil {
+		var policyErr *entities.PolicyError
+		if !errors.As(err, &policyErr) {
+			if err := tx.Rollback(); err != nil {
+				return fmt.Errorf("error during user's withdrawal limits check, cat't rollback transaction: %w", err)
+			}
+			return err
+		}
+		if err := removePreOrder(ctx, tx, userID, order); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return fmt.Errorf("can't remove preorder of rejected withdrawal, cat't rollback transaction: %w", err)
+			}
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("cat't commit transaction during rejecting user's withdrawn: %w", err)
+		}
+		return policyErr
+	}
+
 	_, err = tx.ExecContext(ctx, queryBonusUpdate, amount, userID)
 	if err != nil {
 		return fmt.Errorf("can't make bonuse withdrawn, %w", err)
@@ -227,19 +336,48 @@ func (r *Repo) MakeWithdrawn(ctx context.Context, userID uuid.UUID, amount decim
 		}
 		return fmt.Errorf("can't add withdrawns to user, %w", err)
 	}
+
+	err = insertEntry(ctx, tx, entities.NewLedgerEntry(userID, &order, entities.WITHDRAWAL, amount.Neg(), ""))
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return fmt.Errorf("can't add withdrawns to history, cat't rollback transaction: %w", err)
+		}
+		return err
+	}
+
+	err = insertDeliveries(ctx, tx, userID, entities.WebhookWithdrawCreated, entities.NewWithdrawalEvent(order, amount))
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return fmt.Errorf("can't add withdrawal webhooks, cat't rollback transaction: %w", err)
+		}
+		return err
+	}
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("cat't commit transaction during making user's withdrawn: %w", err)
 	}
 	return
 }
 
+// Remove user's preorder, removal is kept in order's history.
+func removePreOrder(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, order string) error {
+	err := insertStatus(ctx, tx, order, entities.DELETED, nil, entities.SourceWithdrawal)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE order_number = $1 AND user_id = $2 AND is_preorder = TRUE", order, userID)
+	if err != nil {
+		return fmt.Errorf("can't remove preorder: %w", err)
+	}
+	return nil
+}
+
 // Load all orders with not finished preparation status.
 func (r *Repo) LoadPocessing(ctx context.Context) ([]entities.Order, error) {
 	orders := make([]entities.Order, 0)
 	query := `
-	SELECT  user_id, order_number
+	SELECT  user_id, order_number, status
 		FROM orders 
-		WHERE (status = 'NEW' OR status = 'REGISTERED' OR status = 'PROCESSING') AND is_preorder = FALSE
+		WHERE (status = 'NEW' OR status = 'REGISTERED' OR status = 'PROCESSING') AND is_preorder = FALSE AND disputed = FALSE
 	`
 	err := r.db.SelectContext(ctx, &orders, query)
 	if err != nil {
@@ -249,11 +387,239 @@ func (r *Repo) LoadPocessing(ctx context.Context) ([]entities.Order, error) {
 	return orders, nil
 }
 
-func (r *Repo) UpdateStatus(ctx context.Context, order string, status entities.Status) (err error) {
-	_, err = r.db.ExecContext(ctx, "UPDATE orders SET status = $1 WHERE order_number = $2", status, order)
+// Update order's status and non-zero accrual, status change is recorded to order's history with current accrual.
+// Webhook deliveries of final status are created in the same transaction.
+// Returns current owner of order, disputed orders are not updated until decision.
+func (r *Repo) UpdateStatus(ctx context.Context, order string, status entities.Status, accrual decimal.Decimal) (owner uuid.UUID, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("can't update orders status, %w", err)
+		return uuid.Nil, fmt.Errorf("can't start transaction for status update: %w", err)
 	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
+	var stored struct {
+		entities.Order
+		Disputed bool `db:"disputed"`
+	}
+	err = tx.GetContext(ctx, &stored, "SELECT user_id, status, accrual, disputed FROM orders WHERE order_number = $1 FOR UPDATE", order)
+	if err != nil {
+		// Order deleted by user.
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, entities.ErrOrderNotFound
+		}
+		return uuid.Nil, fmt.Errorf("can't get order during update status, %w", err)
+	}
+	if stored.Disputed {
+		return uuid.Nil, entities.ErrOrderDisputed
+	}
+	if !accrual.IsZero() {
+		stored.Accrual = accrual
+	}
+
+	query := `
+	UPDATE orders
+	SET status = $1, accrual = $3, processed = CASE WHEN $1 = 'PROCESSED' THEN now() ELSE processed END
+	WHERE order_number = $2
+	`
+	_, err = tx.ExecContext(ctx, query, status, order, stored.Accrual)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("can't update orders status, %w", err)
+	}
+
+	if stored.Status != status {
+		var accrual *decimal.Decimal
+		if !stored.Accrual.IsZero() {
+			accrual = &stored.Accrual
+		}
+		err = insertStatus(ctx, tx, order, status, accrual, entities.SourceAccrual)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if event := entities.WebhookOrderEvent(status); event != "" {
+			err = insertDeliveries(ctx, tx, stored.UserID, event, entities.NewOrderEvent(order, status, stored.Accrual))
+			if err != nil {
+				return uuid.Nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, fmt.Errorf("cat't commit transaction during status update: %w", err)
+	}
+	return stored.UserID, nil
+}
+
+// User's order, entities.ErrOrderNotFound if order doesn't exist or belongs to other user.
+func (r *Repo) GetOrder(ctx context.Context, userID uuid.UUID, order string) (*entities.Order, error) {
+	query := `
+	SELECT user_id, order_number, uploaded, status, withdrawn, accrual, metadata
+	FROM orders 
+	WHERE is_preorder = FALSE AND user_id = $1 AND order_number = $2
+	`
+	stored := entities.Order{}
+	err := r.db.GetContext(ctx, &stored, query, userID, order)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("can't get order: %w", err)
+	}
+	return &stored, nil
+}
+
+// Order's status transitions in time order.
+func (r *Repo) GetOrderHistory(ctx context.Context, userID uuid.UUID, order string) ([]entities.StatusChange, error) {
+	query := `
+	SELECT status, accrual, source, created
+	FROM order_status_history
+	WHERE order_number = $1 AND user_id = $2
+	ORDER BY created, id
+	`
+	history := []entities.StatusChange{}
+	err := r.db.SelectContext(ctx, &history, query, order, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get order's status history: %w", err)
+	}
+	return history, nil
+}
+
+// Add order's status change to history of order's owner.
+func insertStatus(ctx context.Context, tx sqlx.ExecerContext, order string, status entities.Status, accrual *decimal.Decimal, source string) error {
+	query := `
+	INSERT INTO order_status_history (order_number, user_id, status, accrual, source, created)
+	SELECT order_number, user_id, $2, $3, $4, $5 FROM orders WHERE order_number = $1
+	`
+	_, err := tx.ExecContext(ctx, query, order, status, accrual, source, time.Now())
+	if err != nil {
+		return fmt.Errorf("can't add order's status to history: %w", err)
+	}
+	return nil
+}
+
+// Load processed orders which can still be returned.
+func (r *Repo) LoadReturnable(ctx context.Context, since time.Time) ([]entities.Order, error) {
+	orders := make([]entities.Order, 0)
+	query := `
+	SELECT  user_id, order_number, accrual
+		FROM orders 
+		WHERE status = 'PROCESSED' AND is_preorder = FALSE AND disputed = FALSE AND processed >= $1 AND accrual > 0
+	`
+	err := r.db.SelectContext(ctx, &orders, query, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// Reduce order's accrual and debit the same share of points credited for the order.
+// User's bonuses may become negative, debt is paid off by next accruals.
+func (r *Repo) Clawback(ctx context.Context, order string, accrual decimal.Decimal) (debit decimal.Decimal, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't start transaction for clawback: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var stored entities.Order
+	err = tx.GetContext(ctx, &stored, "SELECT user_id, order_number, accrual FROM orders WHERE order_number = $1 FOR UPDATE", order)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't get order for clawback: %w", err)
+	}
+	if !accrual.LessThan(stored.Accrual) {
+		return decimal.Zero, nil
+	}
+
+	// Points credited to the owner for the order: accrual with tier multiplier and campaigns' extra,
+	// minus previous clawbacks, moved by dispute resolution.
+	queryCredited := `
+	SELECT COALESCE(SUM(amount), 0)
+	FROM ledger
+	WHERE order_number = $1 AND user_id = $2 AND kind IN ('ACCRUAL', 'CAMPAIGN', 'CLAWBACK', 'DISPUTE')
+	`
+	var credited decimal.Decimal
+	err = tx.GetContext(ctx, &credited, queryCredited, order, stored.UserID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't get order's credited points: %w", err)
+	}
+
+	debit = credited.Mul(stored.Accrual.Sub(accrual)).Div(stored.Accrual).Round(2)
+
+	_, err = tx.ExecContext(ctx, "UPDATE orders SET accrual = $1 WHERE order_number = $2", accrual, order)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't update order's accrual during clawback: %w", err)
+	}
+
+	if debit.IsPositive() {
+		_, err = tx.ExecContext(ctx, "UPDATE users SET bonuses = bonuses - $1 WHERE user_id = $2", debit, stored.UserID)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("can't debit user's bonuses during clawback: %w", err)
+		}
+
+		err = insertEntry(ctx, tx, entities.NewLedgerEntry(stored.UserID, &order, entities.CLAWBACK, debit.Neg(), ""))
+		if err != nil {
+			return decimal.Zero, err
+		}
+	}
+
+	err = insertStatus(ctx, tx, order, entities.PROCESSED, &accrual, entities.SourceReturn)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return decimal.Zero, fmt.Errorf("cat't commit transaction during clawback: %w", err)
+	}
 	return
 }
+
+// Delete user's new order, deletion is kept in user's order history.
+func (r *Repo) DeleteOrder(ctx context.Context, userID uuid.UUID, order string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for order deletion: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var stored struct {
+		entities.Order
+		Disputed bool `db:"disputed"`
+	}
+	query := `
+	SELECT user_id, order_number, status, is_preorder, disputed
+	FROM orders
+	WHERE order_number = $1 AND user_id = $2
+	FOR UPDATE
+	`
+	err = tx.GetContext(ctx, &stored, query, order, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entities.ErrOrderNotFound
+		}
+		return fmt.Errorf("can't get order for deletion: %w", err)
+	}
+	if stored.Status != entities.NEW || stored.IsPreOrder || stored.Disputed {
+		return entities.ErrOrderNotDeletable
+	}
+
+	err = insertStatus(ctx, tx, order, entities.DELETED, nil, entities.SourceDeletion)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE order_number = $1", order)
+	if err != nil {
+		return fmt.Errorf("can't delete order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during order deletion: %w", err)
+	}
+	return nil
+}
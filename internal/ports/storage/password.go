@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Password hash of user.
+func (r *Repo) GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	var hash string
+	err := r.db.GetContext(ctx, &hash, "SELECT password_hash FROM users WHERE user_id = $1", userID)
+	if err != nil {
+		return "", fmt.Errorf("can't get user's password: %w", err)
+	}
+	return hash, nil
+}
+
+// Set new password, sessions of user except kept one are revoked.
+func (r *Repo) SetPassword(ctx context.Context, userID uuid.UUID, hash string, keep uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for password change: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = updatePassword(ctx, tx, userID, hash, keep)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during password change: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) AddPasswordReset(ctx context.Context, reset *entities.PasswordReset) error {
+	query := `
+	INSERT INTO password_resets (token_hash, user_id, created, expires)
+	VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, reset.TokenHash, reset.UserID, reset.Created, reset.Expires)
+	if err != nil {
+		return fmt.Errorf("can't add password reset: %w", err)
+	}
+	return nil
+}
+
+// Set new password with reset token. Token is used once, other tokens and all sessions of user are revoked.
+func (r *Repo) ResetPassword(ctx context.Context, tokenHash string, hash string) (uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("can't start transaction for password reset: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT token_hash, user_id, created, expires, used
+	FROM password_resets
+	WHERE token_hash = $1
+	FOR UPDATE
+	`
+	reset := entities.PasswordReset{}
+	err = tx.GetContext(ctx, &reset, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, entities.ErrResetNotValid
+		}
+		return uuid.Nil, fmt.Errorf("can't get password reset: %w", err)
+	}
+	now := time.Now()
+	if reset.Used != nil || reset.Expires.Before(now) {
+		return uuid.Nil, entities.ErrResetNotValid
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE password_resets SET used = $1 WHERE user_id = $2 AND used IS NULL", now, reset.UserID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("can't use password reset: %w", err)
+	}
+
+	err = updatePassword(ctx, tx, reset.UserID, hash, uuid.Nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, fmt.Errorf("cat't commit transaction during password reset: %w", err)
+	}
+	return reset.UserID, nil
+}
+
+// Update password hash and revoke user's sessions except kept one.
+func updatePassword(ctx context.Context, tx sqlx.ExecerContext, userID uuid.UUID, hash string, keep uuid.UUID) error {
+	now := time.Now()
+	_, err := tx.ExecContext(ctx, "UPDATE users SET password_hash = $1, password_changed = $2 WHERE user_id = $3", hash, now, userID)
+	if err != nil {
+		return fmt.Errorf("can't update user's password: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE sessions SET revoked = $1 WHERE user_id = $2 AND session_id <> $3 AND revoked IS NULL", now, userID, keep)
+	if err != nil {
+		return fmt.Errorf("can't revoke user's sessions: %w", err)
+	}
+	return nil
+}
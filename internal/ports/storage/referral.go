@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Find user by referral code.
+func (r *Repo) GetByReferralCode(ctx context.Context, code string) (userID uuid.UUID, err error) {
+	err = r.db.GetContext(ctx, &userID, "SELECT user_id FROM users WHERE referral_code = $1", code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, entities.ErrReferralNotFound
+		}
+		return uuid.Nil, fmt.Errorf("can't find user by referral code: %w", err)
+	}
+	return
+}
+
+func (r *Repo) GetReferralCode(ctx context.Context, userID uuid.UUID) (code string, err error) {
+	err = r.db.GetContext(ctx, &code, "SELECT referral_code FROM users WHERE user_id = $1", userID)
+	if err != nil {
+		return "", fmt.Errorf("can't get user's referral code: %w", err)
+	}
+	return
+}
+
+func (r *Repo) AddReferral(ctx context.Context, referrerID uuid.UUID, refereeID uuid.UUID) error {
+	query := `
+	INSERT INTO referrals (referrer_id, referee_id, created)
+	VALUES ($1, $2, $3)
+	`
+	_, err := r.db.ExecContext(ctx, query, referrerID, refereeID, time.Now())
+	if err != nil {
+		return fmt.Errorf("can't add referral: %w", err)
+	}
+	return nil
+}
+
+// Users invited by referrer.
+func (r *Repo) GetReferrals(ctx context.Context, referrerID uuid.UUID) ([]entities.Referral, error) {
+	query := `
+	SELECT referrer_id, referee_id, login, status, referrer_bonus, referee_bonus, created, rewarded
+	FROM referrals
+	JOIN users ON users.user_id = referrals.referee_id
+	WHERE referrer_id = $1
+	ORDER BY created DESC
+	`
+	referrals := []entities.Referral{}
+	err := r.db.SelectContext(ctx, &referrals, query, referrerID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get user's referrals: %w", err)
+	}
+	return referrals, nil
+}
+
+// Reward referrer and referee once, referrer's bonus limited by cap of rewarded referrals.
+// Return nil referral if referee has no pending referral.
+func (r *Repo) RewardReferral(ctx context.Context, refereeID uuid.UUID, referrerBonus, refereeBonus decimal.Decimal, limit int) (*entities.Referral, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for referral reward: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT referrer_id, referee_id, status, referrer_bonus, referee_bonus, created, rewarded
+	FROM referrals
+	WHERE referee_id = $1 AND status = 'PENDING'
+	FOR UPDATE
+	`
+	referral := entities.Referral{}
+	err = tx.GetContext(ctx, &referral, query, refereeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("can't load pending referral: %w", err)
+	}
+
+	// Lock referrer to count rewards without race.
+	_, err = tx.ExecContext(ctx, "SELECT 1 FROM users WHERE user_id = $1 FOR UPDATE", referral.ReferrerID)
+	if err != nil {
+		return nil, fmt.Errorf("can't lock referrer: %w", err)
+	}
+
+	var rewarded int
+	err = tx.GetContext(ctx, &rewarded, "SELECT count(*) FROM referrals WHERE referrer_id = $1 AND status = 'REWARDED'", referral.ReferrerID)
+	if err != nil {
+		return nil, fmt.Errorf("can't count referrer's rewards: %w", err)
+	}
+
+	referral.Status = entities.REWARDED
+	referral.ReferrerBonus = referrerBonus
+	if rewarded >= limit {
+		referral.Status = entities.CAPPED
+		referral.ReferrerBonus = decimal.Zero
+	}
+	referral.RefereeBonus = refereeBonus
+	now := time.Now()
+	referral.Rewarded = &now
+
+	queryUpdate := `
+	UPDATE referrals
+	SET status = $1, referrer_bonus = $2, referee_bonus = $3, rewarded = $4
+	WHERE referee_id = $5
+	`
+	_, err = tx.ExecContext(ctx, queryUpdate, referral.Status, referral.ReferrerBonus, referral.RefereeBonus, now, refereeID)
+	if err != nil {
+		return nil, fmt.Errorf("can't update referral: %w", err)
+	}
+
+	entries := []*entities.LedgerEntry{
+		entities.NewLedgerEntry(referral.ReferrerID, nil, entities.REFERRAL, referral.ReferrerBonus, "referrer"),
+		entities.NewLedgerEntry(referral.RefereeID, nil, entities.REFERRAL, referral.RefereeBonus, "referee"),
+	}
+	for _, entry := range entries {
+		if !entry.Amount.IsPositive() {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE users SET bonuses = bonuses + $1 WHERE user_id = $2", entry.Amount, entry.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("can't add referral bonus to user: %w", err)
+		}
+		err = insertEntry(ctx, tx, entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during referral reward: %w", err)
+	}
+	return &referral, nil
+}
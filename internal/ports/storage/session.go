@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+func (r *Repo) AddSession(ctx context.Context, session *entities.Session) error {
+	query := `
+	INSERT INTO sessions (user_id, refresh_hash, created, expires)
+	VALUES ($1, $2, $3, $4)
+	RETURNING session_id, (SELECT role FROM users WHERE user_id = $1) AS role
+	`
+	err := r.db.GetContext(ctx, session, query, session.UserID, session.RefreshHash, session.Created, session.Expires)
+	if err != nil {
+		return fmt.Errorf("can't add session: %w", err)
+	}
+	return nil
+}
+
+// Replace session's refresh token. Previous token is kept to detect its reuse,
+// reuse of previous token revokes the session.
+func (r *Repo) RotateSession(ctx context.Context, refreshHash string, newHash string, expires time.Time) (*entities.Session, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't start transaction for session refresh: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	SELECT s.session_id, s.user_id, u.role, s.refresh_hash, s.created, s.expires, s.revoked
+	FROM sessions s
+	JOIN users u ON u.user_id = s.user_id
+	WHERE s.refresh_hash = $1 OR s.previous_hash = $1
+	FOR UPDATE OF s
+	`
+	session := entities.Session{}
+	err = tx.GetContext(ctx, &session, query, refreshHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("can't get session: %w", err)
+	}
+	now := time.Now()
+	if session.Revoked != nil || session.Expires.Before(now) {
+		return nil, entities.ErrSessionRevoked
+	}
+
+	if session.RefreshHash != refreshHash {
+		_, err = tx.ExecContext(ctx, "UPDATE sessions SET revoked = $1 WHERE session_id = $2", now, session.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("can't revoke session: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("cat't commit transaction during session revocation: %w", err)
+		}
+		return nil, entities.ErrRefreshReused
+	}
+
+	queryRotate := `
+	UPDATE sessions
+	SET previous_hash = refresh_hash, refresh_hash = $1, refreshed = $2, expires = $3
+	WHERE session_id = $4
+	`
+	_, err = tx.ExecContext(ctx, queryRotate, newHash, now, expires, session.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("can't rotate session's refresh token: %w", err)
+	}
+	session.RefreshHash = newHash
+	session.Expires = expires
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cat't commit transaction during session refresh: %w", err)
+	}
+	return &session, nil
+}
+
+// Session is not revoked and not expired.
+func (r *Repo) IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	query := `
+	SELECT count(*)
+	FROM sessions
+	WHERE session_id = $1 AND revoked IS NULL AND expires > now()
+	`
+	var active int
+	err := r.db.GetContext(ctx, &active, query, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("can't check session: %w", err)
+	}
+	return active != 0, nil
+}
+
+func (r *Repo) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET revoked = $1 WHERE session_id = $2 AND revoked IS NULL", time.Now(), sessionID)
+	if err != nil {
+		return fmt.Errorf("can't revoke session: %w", err)
+	}
+	return nil
+}
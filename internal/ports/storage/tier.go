@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+func (r *Repo) GetTier(ctx context.Context, userID uuid.UUID) (tier string, err error) {
+	query := `
+	SELECT tier
+	FROM users
+	WHERE user_id = $1
+	`
+	err = r.db.GetContext(ctx, &tier, query, userID)
+	if err != nil {
+		return "", fmt.Errorf("can't get user's tier: %w", err)
+	}
+	return
+}
+
+// Set new user's tier and record change to tier history.
+func (r *Repo) SetTier(ctx context.Context, change *entities.TierChange) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction for tier change: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET tier = $1 WHERE user_id = $2", change.To, change.UserID)
+	if err != nil {
+		return fmt.Errorf("can't update user's tier: %w", err)
+	}
+
+	query := `
+	INSERT INTO tier_history (user_id, tier_from, tier_to, lifetime, changed)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.ExecContext(ctx, query, change.UserID, change.From, change.To, change.Lifetime, change.Changed)
+	if err != nil {
+		return fmt.Errorf("can't record tier change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cat't commit transaction during tier change: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) GetTierHistory(ctx context.Context, userID uuid.UUID) ([]entities.TierChange, error) {
+	query := `
+	SELECT user_id, tier_from, tier_to, lifetime, changed
+	FROM tier_history
+	WHERE user_id = $1
+	ORDER BY changed DESC
+	`
+	history := []entities.TierChange{}
+	err := r.db.SelectContext(ctx, &history, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get user's tier history: %w", err)
+	}
+	return history, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgerrcode"
@@ -20,10 +21,21 @@ func (r *Repo) AddUser(ctx context.Context, login string, hash string) (*uuid.UU
 	`
 	userID := &uuid.UUID{}
 
-	err := r.db.GetContext(ctx, userID, query, login, hash)
+	// Referral code is generated by column's default, insert is repeated with new code on collision.
+	var err error
+	for i := 0; i < referralCodeAttempts; i++ {
+		err = r.db.GetContext(ctx, userID, query, login, hash)
+		if !isReferralCollision(err) {
+			break
+		}
+		zap.S().Infoln("Referral code collision, new code is generated for: ", login)
+	}
 	if err != nil {
 		var pgErr *pq.Error
-		// if URL exist in DataBase
+		if isReferralCollision(err) {
+			return nil, fmt.Errorf("can't generate unique referral code: %s", err.Error())
+		}
+		// if login exist in DataBase
 		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
 			return nil, pgErr
 		}
@@ -32,12 +44,23 @@ func (r *Repo) AddUser(ctx context.Context, login string, hash string) (*uuid.UU
 	return userID, nil
 }
 
-// Retrive User by login.
+// Unique constraint of users' referral codes.
+const referralCodeConstraint = "users_referral_code_key"
+
+// Attempts to add user with new referral code.
+const referralCodeAttempts = 5
+
+func isReferralCollision(err error) bool {
+	var pgErr *pq.Error
+	return errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code && pgErr.Constraint == referralCodeConstraint
+}
+
+// Retrive User by login, case is ignored.
 func (r *Repo) GetByLogin(ctx context.Context, login string) (*entities.User, error) {
 	query := `
-	SELECT user_id, password_hash 
+	SELECT user_id, login, password_hash 
 	FROM users 
-	WHERE login = $1
+	WHERE lower(login) = lower($1)
 	`
 	user := entities.User{Login: login}
 	zap.S().Infoln("user login: ", login)
@@ -47,3 +70,12 @@ func (r *Repo) GetByLogin(ctx context.Context, login string) (*entities.User, er
 	}
 	return &user, nil
 }
+
+// Time of user's registration.
+func (r *Repo) GetRegistered(ctx context.Context, userID uuid.UUID) (registered time.Time, err error) {
+	err = r.db.GetContext(ctx, &registered, "SELECT registered FROM users WHERE user_id = $1", userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't get user's registration time: %w", err)
+	}
+	return
+}
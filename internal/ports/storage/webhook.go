@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+func (r *Repo) AddWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	query := `
+	INSERT INTO webhooks (user_id, url, secret, events, created)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING webhook_id
+	`
+	err := r.db.GetContext(ctx, &webhook.WebhookID, query, webhook.UserID, webhook.URL, webhook.Secret, webhook.Events, webhook.Created)
+	if err != nil {
+		return fmt.Errorf("can't add webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) GetWebhooks(ctx context.Context, userID uuid.UUID) ([]entities.Webhook, error) {
+	query := `
+	SELECT webhook_id, user_id, url, secret, events, created
+	FROM webhooks
+	WHERE user_id = $1
+	ORDER BY created DESC
+	`
+	webhooks := []entities.Webhook{}
+	err := r.db.SelectContext(ctx, &webhooks, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *Repo) DeleteWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE webhook_id = $1 AND user_id = $2", webhookID, userID)
+	if err != nil {
+		return fmt.Errorf("can't delete webhook: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't delete webhook: %w", err)
+	}
+	if rows == 0 {
+		return entities.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Create deliveries of event for all user's webhooks subscribed to it.
+// Called in transaction of the change (transactional outbox), so event is never lost or sent for rolled back change.
+func insertDeliveries(ctx context.Context, tx sqlx.ExecerContext, userID uuid.UUID, event string, data any) error {
+	created := time.Now()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("can't marshal webhook data: %w", err)
+	}
+	payload, err := json.Marshal(entities.WebhookPayload{Event: event, Created: created, Data: raw})
+	if err != nil {
+		return fmt.Errorf("can't marshal webhook payload: %w", err)
+	}
+
+	query := `
+	INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt, created)
+	SELECT webhook_id, $2, $3, $4, $4
+	FROM webhooks
+	WHERE user_id = $1 AND $2 = ANY(events)
+	`
+	_, err = tx.ExecContext(ctx, query, userID, event, payload, created)
+	if err != nil {
+		return fmt.Errorf("can't add webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Take due deliveries for sending. Taken deliveries are postponed by lease, so other workers skip them.
+func (r *Repo) TakeDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entities.Delivery, error) {
+	query := `
+	WITH taken AS (
+		UPDATE webhook_deliveries
+		SET next_attempt = now() + $2 * interval '1 second'
+		WHERE delivery_id IN (
+			SELECT delivery_id
+			FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt <= now()
+			ORDER BY next_attempt
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING delivery_id, webhook_id, event, payload, status, attempts, next_attempt, response_code, error, created, delivered
+	)
+	SELECT taken.*, w.url, w.secret
+	FROM taken
+	JOIN webhooks w ON w.webhook_id = taken.webhook_id
+	`
+	deliveries := []entities.Delivery{}
+	err := r.db.SelectContext(ctx, &deliveries, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("can't take webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Save result of delivery attempt.
+func (r *Repo) UpdateDelivery(ctx context.Context, delivery *entities.Delivery) error {
+	query := `
+	UPDATE webhook_deliveries
+	SET status = $1, attempts = $2, next_attempt = $3, response_code = $4, error = $5, delivered = $6
+	WHERE delivery_id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.NextAttempt, delivery.ResponseCode,
+		delivery.Error, delivery.Delivered, delivery.DeliveryID)
+	if err != nil {
+		return fmt.Errorf("can't update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// Recent deliveries of user's webhook.
+func (r *Repo) GetDeliveries(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, limit int) ([]entities.Delivery, error) {
+	var exist int
+	err := r.db.GetContext(ctx, &exist, "SELECT count(*) FROM webhooks WHERE webhook_id = $1 AND user_id = $2", webhookID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook: %w", err)
+	}
+	if exist == 0 {
+		return nil, entities.ErrWebhookNotFound
+	}
+
+	query := `
+	SELECT delivery_id, webhook_id, event, payload, status, attempts, next_attempt, response_code, error, created, delivered
+	FROM webhook_deliveries
+	WHERE webhook_id = $1
+	ORDER BY created DESC, id DESC
+	LIMIT $2
+	`
+	deliveries := []entities.Delivery{}
+	err = r.db.SelectContext(ctx, &deliveries, query, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
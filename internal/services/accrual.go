@@ -14,21 +14,28 @@ import (
 type AccrualService struct {
 	stor          AccrualRepo
 	accrualClient AccrualClient
+	tierSrv       *TierService
+	campaignSrv   *CampaignService
+	referralSrv   *ReferralService
+	returnWindow  time.Duration
+	bus           *EventBus
 }
 
 type AccrualRepo interface {
 	LoadPocessing(ctx context.Context) ([]entities.Order, error)
-	UpdateStatus(ctx context.Context, order string, status entities.Status) (err error)
-	SetAccrual(ctx context.Context, order string, accrual decimal.Decimal) (err error)
-	AddBonuses(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (err error)
+	UpdateStatus(ctx context.Context, order string, status entities.Status, accrual decimal.Decimal) (owner uuid.UUID, err error)
+	AddBonuses(ctx context.Context, entry *entities.LedgerEntry) (err error)
+	LoadReturnable(ctx context.Context, since time.Time) ([]entities.Order, error)
+	Clawback(ctx context.Context, order string, accrual decimal.Decimal) (debit decimal.Decimal, err error)
 }
 
 type AccrualClient interface {
 	GetOrderStatus(orderNr string) (*entities.AccrualResponce, error)
+	RegisterOrder(orderNr string, goods []entities.Good) error
 }
 
-func NewAccrualService(accRepo AccrualRepo, ac AccrualClient) *AccrualService {
-	return &AccrualService{stor: accRepo, accrualClient: ac}
+func NewAccrualService(accRepo AccrualRepo, ac AccrualClient, tierSrv *TierService, campaignSrv *CampaignService, referralSrv *ReferralService, returnWindow time.Duration, bus *EventBus) *AccrualService {
+	return &AccrualService{stor: accRepo, accrualClient: ac, tierSrv: tierSrv, campaignSrv: campaignSrv, referralSrv: referralSrv, returnWindow: returnWindow, bus: bus}
 }
 
 func (o *AccrualService) Run(ctx context.Context) {
@@ -39,6 +46,18 @@ func (o *AccrualService) Run(ctx context.Context) {
 			o.FetchAccrual(ctx)
 		}
 	}(ctx, o)
+
+	// Returns are checked only inside return window.
+	if o.returnWindow == 0 {
+		return
+	}
+	returns := time.NewTicker(config.CheckReturns * time.Second)
+	go func(ctx context.Context, o *AccrualService) {
+		for {
+			<-returns.C
+			o.FetchReturns(ctx)
+		}
+	}(ctx, o)
 }
 
 func (o *AccrualService) FetchAccrual(ctx context.Context) {
@@ -49,11 +68,15 @@ func (o *AccrualService) FetchAccrual(ctx context.Context) {
 
 	for _, order := range loadOrders {
 		// Set order status to PROCESSING in database
-		err := o.stor.UpdateStatus(ctx, order.OrderNr, entities.Status(entities.PROCESSING))
+		owner, err := o.stor.UpdateStatus(ctx, order.OrderNr, entities.Status(entities.PROCESSING), decimal.Zero)
 		if err != nil {
 			zap.S().Errorln("Can't update status to PROCESSING in database", err)
 			continue
 		}
+		order.UserID = owner
+		if order.Status != entities.PROCESSING {
+			o.bus.Publish(order.UserID, entities.EventOrderStatus, entities.OrderEvent{OrderNr: order.OrderNr, Status: entities.PROCESSING})
+		}
 		//fech status and accrual from Accrual system
 		accResp, err := o.accrualClient.GetOrderStatus(order.OrderNr)
 		if err != nil {
@@ -68,23 +91,110 @@ func (o *AccrualService) FetchAccrual(ctx context.Context) {
 
 		//if status PROCESSED or INVALID - update db and remove from orders
 		if status == entities.PROCESSED || status == entities.INVALID {
-			err = o.stor.UpdateStatus(ctx, order.OrderNr, status)
+			//set accruals and status, status history keeps accrual
+			// Order deleted by user is not credited, the number may be uploaded again.
+			// Disputed order is polled again after decision and credited to its owner at that time.
+			owner, err = o.stor.UpdateStatus(ctx, order.OrderNr, status, accrual)
 			if err != nil {
-				zap.S().Errorln("Get error during deleted poccessed order", err)
+				zap.S().Errorln("Get error during update status of poccessed order", order.OrderNr, err)
+				continue
 			}
-			//set accruals to the order
-			if accrual != decimal.Zero {
-				err = o.stor.SetAccrual(ctx, order.OrderNr, accrual)
-				if err != nil {
-					zap.S().Errorln("Get error during deleted poccessed order", err)
-				}
+			order.UserID = owner
+			o.bus.Publish(order.UserID, entities.EventOrderStatus, entities.NewOrderEvent(order.OrderNr, status, accrual))
+
+			//add accruals with tier multiplier to user's bonus balance
+			if accrual.IsPositive() {
+				o.creditAccrual(ctx, order, accrual)
 			}
 
-			//add accruals to user's bonus balance
-			err = o.stor.AddBonuses(ctx, order.UserID, accrual)
-			if err != nil {
-				zap.S().Errorln("get error update user's balance", err)
+			// extra points of promotional campaigns
+			if status == entities.PROCESSED {
+				o.campaignSrv.Apply(ctx, order, accrual)
+
+				// referral rewards after referee's first processed order
+				o.referralSrv.Reward(ctx, order.UserID)
 			}
 		}
 	}
 }
+
+// Credit order's accrual multiplied by user's tier multiplier.
+func (o *AccrualService) creditAccrual(ctx context.Context, order entities.Order, accrual decimal.Decimal) {
+	credit := accrual
+	label := ""
+	tier, err := o.tierSrv.Multiplier(ctx, order.UserID)
+	if err != nil {
+		zap.S().Errorln("Can't get user's tier, credit without multiplier: ", err)
+	} else {
+		credit = accrual.Mul(tier.Multiplier).Round(2)
+		label = tier.Name
+	}
+
+	orderNr := order.OrderNr
+	err = o.stor.AddBonuses(ctx, entities.NewLedgerEntry(order.UserID, &orderNr, entities.ACCRUAL, credit, label))
+	if err != nil {
+		zap.S().Errorln("get error update user's balance", err)
+		return
+	}
+	o.bus.Publish(order.UserID, entities.EventBalance, entities.BalanceEvent{Kind: entities.ACCRUAL, Amount: credit.InexactFloat64(), OrderNr: &orderNr})
+
+	// Accruals may move user to the next tier.
+	_, _, err = o.tierSrv.UpdateTier(ctx, order.UserID)
+	if err != nil {
+		zap.S().Errorln("Can't update user's tier: ", err)
+	}
+}
+
+// Re-check processed orders in return window, debit points if accrual was reduced.
+func (o *AccrualService) FetchReturns(ctx context.Context) {
+	orders, err := o.stor.LoadReturnable(ctx, time.Now().Add(-o.returnWindow))
+	if err != nil {
+		zap.S().Errorln("Can't load processed orders for returns check: ", err)
+		return
+	}
+
+	for _, order := range orders {
+		accResp, err := o.accrualClient.GetOrderStatus(order.OrderNr)
+		if err != nil {
+			zap.S().Errorln("Get order status prepare error: ", err)
+			continue
+		}
+
+		accrual := decimal.NewFromFloat(accResp.Accrual)
+		if entities.Status(accResp.Status) != entities.PROCESSED || !accrual.LessThan(order.Accrual) {
+			continue
+		}
+
+		debit, err := o.stor.Clawback(ctx, order.OrderNr, accrual)
+		if err != nil {
+			zap.S().Errorln("Can't make clawback for order ", order.OrderNr, ": ", err)
+			continue
+		}
+		zap.S().Infoln("Order ", order.OrderNr, " returned, accrual: ", order.Accrual, " -> ", accrual, " debited: ", debit)
+		if debit.IsPositive() {
+			orderNr := order.OrderNr
+			o.bus.Publish(order.UserID, entities.EventBalance, entities.BalanceEvent{Kind: entities.CLAWBACK, Amount: debit.Neg().InexactFloat64(), OrderNr: &orderNr})
+		}
+
+		// Returns may move user to the previous tier.
+		_, _, err = o.tierSrv.UpdateTier(ctx, order.UserID)
+		if err != nil {
+			zap.S().Errorln("Can't update user's tier: ", err)
+		}
+	}
+}
+
+// Forward order's goods to Accrual system in background, order is processed by the usual polling.
+func (o *AccrualService) RegisterOrder(order *entities.Order) {
+	if order.Purchase == nil || len(order.Purchase.Goods) == 0 {
+		return
+	}
+	go func(orderNr string, goods []entities.Good) {
+		err := o.accrualClient.RegisterOrder(orderNr, goods)
+		if err != nil {
+			zap.S().Errorln("Can't register order in Accrual system: ", orderNr, err)
+			return
+		}
+		zap.S().Infoln("Order registered in Accrual system: ", orderNr)
+	}(order.OrderNr, order.Purchase.Goods)
+}
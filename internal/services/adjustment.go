@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Manual balance adjustments by admins with four-eyes approval above threshold.
+type AdjustmentService struct {
+	stor      AdjustmentRepo
+	threshold decimal.Decimal
+}
+
+type AdjustmentRepo interface {
+	AddAdjustment(ctx context.Context, adj *entities.Adjustment) error
+	DecideAdjustment(ctx context.Context, adjustmentID uuid.UUID, admin string, approve bool) (*entities.Adjustment, error)
+	GetAdjustments(ctx context.Context, status entities.AdjustmentStatus) ([]entities.Adjustment, error)
+	GetAudit(ctx context.Context, userID *uuid.UUID) ([]entities.AuditRecord, error)
+}
+
+func NewAdjustmentService(stor AdjustmentRepo, threshold decimal.Decimal) *AdjustmentService {
+	return &AdjustmentService{stor: stor, threshold: threshold}
+}
+
+// Create adjustment. It is applied at once if amount is not above threshold, otherwise waits for approval.
+func (a *AdjustmentService) AddAdjustment(ctx context.Context, adj *entities.Adjustment) error {
+	adj.Status = entities.AWAITING
+	if a.threshold.IsZero() || adj.Amount.Abs().LessThanOrEqual(a.threshold) {
+		adj.Status = entities.APPLIED
+		adj.DecidedBy = &adj.CreatedBy
+		adj.Decided = &adj.Created
+	}
+	return a.stor.AddAdjustment(ctx, adj)
+}
+
+func (a *AdjustmentService) Approve(ctx context.Context, adjustmentID uuid.UUID, admin string) (*entities.Adjustment, error) {
+	return a.stor.DecideAdjustment(ctx, adjustmentID, admin, true)
+}
+
+func (a *AdjustmentService) Reject(ctx context.Context, adjustmentID uuid.UUID, admin string) (*entities.Adjustment, error) {
+	return a.stor.DecideAdjustment(ctx, adjustmentID, admin, false)
+}
+
+func (a *AdjustmentService) GetAdjustments(ctx context.Context, status entities.AdjustmentStatus) ([]entities.Adjustment, error) {
+	return a.stor.GetAdjustments(ctx, status)
+}
+
+func (a *AdjustmentService) GetAudit(ctx context.Context, userID *uuid.UUID) ([]entities.AuditRecord, error) {
+	return a.stor.GetAudit(ctx, userID)
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Users and orders lookup and roles management for admins.
+type AdminService struct {
+	stor AdminRepo
+}
+
+type AdminRepo interface {
+	GetUser(ctx context.Context, userID uuid.UUID) (*entities.UserInfo, error)
+	FindUser(ctx context.Context, login string) (*entities.UserInfo, error)
+	SetRole(ctx context.Context, admin string, userID uuid.UUID, role string) (*entities.UserInfo, error)
+	InspectOrder(ctx context.Context, order string) (*entities.OrderDetail, error)
+}
+
+func NewAdminService(stor AdminRepo) *AdminService {
+	return &AdminService{stor: stor}
+}
+
+func (s *AdminService) GetUser(ctx context.Context, userID uuid.UUID) (*entities.UserInfo, error) {
+	return s.stor.GetUser(ctx, userID)
+}
+
+func (s *AdminService) FindUser(ctx context.Context, login string) (*entities.UserInfo, error) {
+	return s.stor.FindUser(ctx, entities.NormalizeLogin(login))
+}
+
+// Set user's role, user has to login again.
+func (s *AdminService) SetRole(ctx context.Context, admin string, userID uuid.UUID, role string) (*entities.UserInfo, error) {
+	if !entities.Roles[role] {
+		return nil, entities.ErrWrongRole
+	}
+	return s.stor.SetRole(ctx, admin, userID, role)
+}
+
+func (s *AdminService) InspectOrder(ctx context.Context, order string) (*entities.OrderInspection, error) {
+	detail, err := s.stor.InspectOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.OrderInspection{Detail: *detail}, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/shopspring/decimal"
@@ -10,26 +11,27 @@ import (
 )
 
 type CalculationService struct {
-	stor CalcRepo
+	stor   CalcRepo
+	policy entities.WithdrawPolicy
+	bus    *EventBus
 }
 
 type CalcRepo interface {
 	GetBonuses(ctx context.Context, userID uuid.UUID) (accrual decimal.Decimal, err error)
 	GetWithdrawn(ctx context.Context, userID uuid.UUID) (accrual decimal.Decimal, err error)
+	GetHeld(ctx context.Context, userID uuid.UUID) (held decimal.Decimal, err error)
 	GetWithdrawals(ctx context.Context, userID uuid.UUID) (withdrawn decimal.Decimal, err error)
 	Withdrawals(ctx context.Context, userID uuid.UUID) ([]entities.Withdrawals, error)
-	IsPreOrder(ctx context.Context, userID uuid.UUID, order string) (isPreOrder bool, err error)
+	GetHistory(ctx context.Context, userID uuid.UUID) ([]entities.LedgerEntry, error)
 	MovePreOrder(ctx context.Context, order *entities.Order) (err error)
 	SetAccrual(ctx context.Context, order string, accrual decimal.Decimal) (err error)
-	MakeWithdrawn(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error
+	MakeWithdrawn(ctx context.Context, userID uuid.UUID, order string, amount decimal.Decimal, limits entities.WithdrawLimits) error
+	GetRegistered(ctx context.Context, userID uuid.UUID) (registered time.Time, err error)
+	GetBalanceAt(ctx context.Context, userID uuid.UUID, at time.Time) (bonuses, withdrawn, held decimal.Decimal, err error)
 }
 
-func NewCalcService(stor CalcRepo) *CalculationService {
-	return &CalculationService{stor: stor}
-}
-
-func (m *CalculationService) IsPreOrder(ctx context.Context, userID uuid.UUID, order string) (isPreOrder bool, err error) {
-	return m.stor.IsPreOrder(ctx, userID, order)
+func NewCalcService(stor CalcRepo, policy entities.WithdrawPolicy, bus *EventBus) *CalculationService {
+	return &CalculationService{stor: stor, policy: policy, bus: bus}
 }
 
 // Make preorder (created with withdrawals) regular order.
@@ -65,6 +67,44 @@ func (m *CalculationService) GetWithdrawn(ctx context.Context, userID uuid.UUID)
 	return
 }
 
+// Bonuses reserved by active holds.
+func (m *CalculationService) GetHeld(ctx context.Context, userID uuid.UUID) (held decimal.Decimal, err error) {
+	held, err = m.stor.GetHeld(ctx, userID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("can't get user's held bonuses: %w", err)
+	}
+	return
+}
+
+// User's current balance.
+func (m *CalculationService) GetBalance(ctx context.Context, userID uuid.UUID) (*entities.UserBalance, error) {
+	bonuses, err := m.GetBonuses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawn, err := m.GetWithdrawn(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := m.GetHeld(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewUserBalance(bonuses, withdrawn, held), nil
+}
+
+// User's balance at time, restored from balance history.
+func (m *CalculationService) GetBalanceAt(ctx context.Context, userID uuid.UUID, at time.Time) (*entities.UserBalance, error) {
+	bonuses, withdrawn, held, err := m.stor.GetBalanceAt(ctx, userID, at)
+	if err != nil {
+		return nil, err
+	}
+	return entities.NewUserBalance(bonuses, withdrawn, held), nil
+}
+
 func (m *CalculationService) CheckBalance(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (isEnough bool, err error) {
 	bonuses, err := m.stor.GetBonuses(ctx, userID)
 	if err != nil {
@@ -84,8 +124,59 @@ func (m *CalculationService) GetWithdrawals(ctx context.Context, userID uuid.UUI
 	return
 }
 
+// All movements of user's bonuses.
+func (m *CalculationService) GetHistory(ctx context.Context, userID uuid.UUID) (history []entities.LedgerEntry, err error) {
+	history, err = m.stor.GetHistory(ctx, userID)
+	return
+}
+
 // Move user's amount from bonuses to withdrawals.
-func (m *CalculationService) MakeWithdrawn(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (err error) {
-	err = m.stor.MakeWithdrawn(ctx, userID, amount)
+// Return *entities.PolicyError if daily or monthly limit is exceeded.
+func (m *CalculationService) MakeWithdrawn(ctx context.Context, userID uuid.UUID, order string, amount decimal.Decimal) (err error) {
+	err = m.stor.MakeWithdrawn(ctx, userID, order, amount, m.Limits())
+	if err != nil {
+		return
+	}
+	m.bus.Publish(userID, entities.EventBalance, entities.NewWithdrawalEvent(order, amount))
 	return
 }
+
+// Daily and monthly withdrawal limits at the moment.
+func (m *CalculationService) Limits() entities.WithdrawLimits {
+	return m.policy.Limits(time.Now())
+}
+
+// Check withdrawal against policy. Return *entities.PolicyError if withdrawal not allowed.
+// Daily and monthly limits are checked by storage on debit, so concurrent withdrawals can't exceed them.
+func (m *CalculationService) CheckPolicy(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, orderSum decimal.Decimal) error {
+	p := m.policy
+
+	if !p.Min.IsZero() && amount.LessThan(p.Min) {
+		return entities.NewPolicyError(entities.WithdrawBelowMin, "minimal withdrawal is "+p.Min.String())
+	}
+
+	if !p.Max.IsZero() && amount.GreaterThan(p.Max) {
+		return entities.NewPolicyError(entities.WithdrawAboveMax, "maximal withdrawal is "+p.Max.String())
+	}
+
+	if !p.Share.IsZero() {
+		if !orderSum.IsPositive() {
+			return entities.NewPolicyError(entities.OrderSumRequired, "order sum is required")
+		}
+		if amount.GreaterThan(orderSum.Mul(p.Share)) {
+			return entities.NewPolicyError(entities.OrderShareExceeded, "maximal share of the order paid with bonuses is "+p.Share.String())
+		}
+	}
+
+	if p.Cooldown != 0 {
+		registered, err := m.stor.GetRegistered(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if time.Now().Before(registered.Add(p.Cooldown)) {
+			return entities.NewPolicyError(entities.RegistrationCooldown, "withdrawals allowed after "+registered.Add(p.Cooldown).Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
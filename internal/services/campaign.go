@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Promotional campaigns: extra points on top of order's accrual.
+type CampaignService struct {
+	stor CampaignRepo
+}
+
+type CampaignRepo interface {
+	AddCampaign(ctx context.Context, campaign *entities.Campaign) error
+	GetCampaigns(ctx context.Context) ([]entities.Campaign, error)
+	GetActiveCampaigns(ctx context.Context, at time.Time) ([]entities.Campaign, error)
+	SetCampaignPaused(ctx context.Context, campaignID uuid.UUID, paused bool) error
+	GetCampaignReport(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignReport, error)
+	CountProcessed(ctx context.Context, userID uuid.UUID) (processed int, err error)
+	CreditCampaign(ctx context.Context, campaignID uuid.UUID, entry *entities.LedgerEntry) error
+}
+
+func NewCampaignService(stor CampaignRepo) *CampaignService {
+	return &CampaignService{stor: stor}
+}
+
+func (c *CampaignService) AddCampaign(ctx context.Context, campaign *entities.Campaign) error {
+	return c.stor.AddCampaign(ctx, campaign)
+}
+
+func (c *CampaignService) GetCampaigns(ctx context.Context) ([]entities.Campaign, error) {
+	return c.stor.GetCampaigns(ctx)
+}
+
+// Pause or resume campaign.
+func (c *CampaignService) SetPaused(ctx context.Context, campaignID uuid.UUID, paused bool) error {
+	return c.stor.SetCampaignPaused(ctx, campaignID, paused)
+}
+
+func (c *CampaignService) GetReport(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignReport, error) {
+	return c.stor.GetCampaignReport(ctx, campaignID)
+}
+
+// Credit extra points of active campaigns for processed order.
+// All eligible stackable campaigns are applied, from not stackable - only one with the biggest extra.
+func (c *CampaignService) Apply(ctx context.Context, order entities.Order, accrual decimal.Decimal) {
+	campaigns, err := c.stor.GetActiveCampaigns(ctx, time.Now())
+	if err != nil {
+		zap.S().Errorln("Can't load active campaigns: ", err)
+		return
+	}
+	if len(campaigns) == 0 {
+		return
+	}
+
+	processed, err := c.stor.CountProcessed(ctx, order.UserID)
+	if err != nil {
+		zap.S().Errorln("Can't check user's first order: ", err)
+		return
+	}
+
+	var best *entities.Campaign
+	apply := make([]entities.Campaign, 0)
+	for i := range campaigns {
+		campaign := campaigns[i]
+		// Eligibility.
+		if campaign.FirstOrder && processed != 1 {
+			continue
+		}
+		if accrual.LessThan(campaign.MinAccrual) {
+			continue
+		}
+
+		// Stacking policy.
+		if campaign.Stackable {
+			apply = append(apply, campaign)
+			continue
+		}
+		if best == nil || campaign.Extra(accrual).GreaterThan(best.Extra(accrual)) {
+			best = &campaigns[i]
+		}
+	}
+	if best != nil {
+		apply = append(apply, *best)
+	}
+
+	for _, campaign := range apply {
+		extra := campaign.Extra(accrual)
+		if !extra.IsPositive() {
+			continue
+		}
+		orderNr := order.OrderNr
+		entry := entities.NewLedgerEntry(order.UserID, &orderNr, entities.CAMPAIGN, extra, campaign.Name)
+		err = c.stor.CreditCampaign(ctx, campaign.CampaignID, entry)
+		if err != nil {
+			zap.S().Errorln(fmt.Sprintf("Can't credit campaign %s for order %s: ", campaign.Name, orderNr), err)
+			continue
+		}
+		zap.S().Infoln("Campaign ", campaign.Name, " credited ", extra, " for order ", orderNr)
+	}
+}
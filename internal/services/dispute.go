@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Ownership disputes of orders uploaded by other users.
+type DisputeService struct {
+	stor DisputeRepo
+	bus  *EventBus
+}
+
+type DisputeRepo interface {
+	AddDispute(ctx context.Context, dispute *entities.Dispute) error
+	DecideDispute(ctx context.Context, disputeID uuid.UUID, admin string, reassign bool, note string) (*entities.Dispute, error)
+	GetDisputes(ctx context.Context, claimant *uuid.UUID, status entities.DisputeStatus) ([]entities.Dispute, error)
+}
+
+func NewDisputeService(stor DisputeRepo, bus *EventBus) *DisputeService {
+	return &DisputeService{stor: stor, bus: bus}
+}
+
+// Open dispute, order's crediting is paused until admin's decision.
+func (d *DisputeService) AddDispute(ctx context.Context, dispute *entities.Dispute) error {
+	return d.stor.AddDispute(ctx, dispute)
+}
+
+// Move order and its accrual to claimant.
+func (d *DisputeService) Reassign(ctx context.Context, disputeID uuid.UUID, admin string, note string) (*entities.Dispute, error) {
+	dispute, err := d.stor.DecideDispute(ctx, disputeID, admin, true, note)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Moved.IsPositive() {
+		d.bus.Publish(dispute.Owner, entities.EventBalance, entities.BalanceEvent{Kind: entities.DISPUTE, Amount: dispute.Moved.Neg().InexactFloat64(), OrderNr: &dispute.OrderNr})
+		d.bus.Publish(dispute.Claimant, entities.EventBalance, entities.BalanceEvent{Kind: entities.DISPUTE, Amount: dispute.Moved.InexactFloat64(), OrderNr: &dispute.OrderNr})
+	}
+	return dispute, nil
+}
+
+func (d *DisputeService) Reject(ctx context.Context, disputeID uuid.UUID, admin string, note string) (*entities.Dispute, error) {
+	return d.stor.DecideDispute(ctx, disputeID, admin, false, note)
+}
+
+// User's disputes.
+func (d *DisputeService) GetUserDisputes(ctx context.Context, userID uuid.UUID) ([]entities.Dispute, error) {
+	return d.stor.GetDisputes(ctx, &userID, "")
+}
+
+func (d *DisputeService) GetDisputes(ctx context.Context, status entities.DisputeStatus) ([]entities.Dispute, error) {
+	return d.stor.GetDisputes(ctx, nil, status)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Buffer of subscriber's channel, slow subscriber loses events and can resume by last event id.
+const subscriberBuffer = 16
+
+// In-process event bus: services publish user's events, subscribers get events of one user.
+// Last events are kept in ring buffer for resume after reconnect.
+type EventBus struct {
+	mu   sync.Mutex
+	seq  uint64
+	ring []entities.Event
+	next int
+	subs map[uuid.UUID]map[chan entities.Event]struct{}
+}
+
+func NewEventBus(size int) *EventBus {
+	return &EventBus{ring: make([]entities.Event, 0, size), subs: make(map[uuid.UUID]map[chan entities.Event]struct{})}
+}
+
+// Publish user's event. Nil bus ignores events.
+func (b *EventBus) Publish(userID uuid.UUID, kind string, data any) {
+	if b == nil {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		zap.S().Errorln("Can't marshal event data: ", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := entities.Event{ID: b.seq, UserID: userID, Kind: kind, Data: raw, Created: time.Now()}
+
+	if len(b.ring) < cap(b.ring) {
+		b.ring = append(b.ring, event)
+	} else if cap(b.ring) != 0 {
+		b.ring[b.next] = event
+		b.next = (b.next + 1) % cap(b.ring)
+	}
+
+	for ch := range b.subs[userID] {
+		select {
+		case ch <- event:
+		default:
+			zap.S().Debugln("Subscriber is slow, event dropped: ", event.ID)
+		}
+	}
+}
+
+// Subscribe to user's events. Return user's events after lastID from buffer and function to unsubscribe.
+func (b *EventBus) Subscribe(userID uuid.UUID, lastID uint64) (events <-chan entities.Event, missed []entities.Event, cancel func()) {
+	ch := make(chan entities.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastID != 0 {
+		// Ring buffer from the oldest event.
+		for i := 0; i < len(b.ring); i++ {
+			event := b.ring[(b.next+i)%len(b.ring)]
+			if event.UserID == userID && event.ID > lastID {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan entities.Event]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs[userID], ch)
+		if len(b.subs[userID]) == 0 {
+			delete(b.subs, userID)
+		}
+	}
+	return ch, missed, cancel
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Authorization holds: reserve bonuses, capture them as withdrawal or release back to user.
+type HoldService struct {
+	stor HoldRepo
+	ttl  time.Duration
+	bus  *EventBus
+}
+
+type HoldRepo interface {
+	CreateHold(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, expires time.Time, limits entities.WithdrawLimits) (*entities.Hold, error)
+	GetHolds(ctx context.Context, userID uuid.UUID) ([]entities.Hold, error)
+	CaptureHold(ctx context.Context, userID uuid.UUID, holdID uuid.UUID, order string) (*entities.Hold, error)
+	VoidHold(ctx context.Context, userID uuid.UUID, holdID uuid.UUID) (*entities.Hold, error)
+	ExpireHolds(ctx context.Context, now time.Time) (expired int, err error)
+}
+
+func NewHoldService(stor HoldRepo, ttl time.Duration, bus *EventBus) *HoldService {
+	return &HoldService{stor: stor, ttl: ttl, bus: bus}
+}
+
+// Run expiration of stale holds.
+func (h *HoldService) Run(ctx context.Context) {
+	expire := time.NewTicker(config.CheckHolds * time.Second)
+	go func(ctx context.Context, h *HoldService) {
+		for {
+			select {
+			case <-ctx.Done():
+				expire.Stop()
+				return
+			case <-expire.C:
+				h.ExpireHolds(ctx)
+			}
+		}
+	}(ctx, h)
+}
+
+func (h *HoldService) ExpireHolds(ctx context.Context) {
+	expired, err := h.stor.ExpireHolds(ctx, time.Now())
+	if err != nil {
+		zap.S().Errorln("Can't expire holds: ", err)
+		return
+	}
+	if expired != 0 {
+		zap.S().Infoln("Expired holds: ", expired)
+	}
+}
+
+// Reserve user's bonuses. Return entities.ErrNotEnoughBonuses if balance is less then amount,
+// *entities.PolicyError if reservation exceeds withdrawal limits.
+func (h *HoldService) CreateHold(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, limits entities.WithdrawLimits) (*entities.Hold, error) {
+	hold, err := h.stor.CreateHold(ctx, userID, amount, time.Now().Add(h.ttl), limits)
+	if err != nil {
+		return nil, fmt.Errorf("can't create hold: %w", err)
+	}
+	return hold, nil
+}
+
+func (h *HoldService) GetHolds(ctx context.Context, userID uuid.UUID) ([]entities.Hold, error) {
+	return h.stor.GetHolds(ctx, userID)
+}
+
+// Turn hold to the withdrawal against order number.
+func (h *HoldService) CaptureHold(ctx context.Context, userID uuid.UUID, holdID uuid.UUID, order string) (*entities.Hold, error) {
+	hold, err := h.stor.CaptureHold(ctx, userID, holdID, order)
+	if err != nil {
+		return nil, fmt.Errorf("can't capture hold: %w", err)
+	}
+	h.bus.Publish(userID, entities.EventBalance, entities.NewWithdrawalEvent(order, hold.Amount))
+	return hold, nil
+}
+
+// Release hold, return bonuses to user.
+func (h *HoldService) VoidHold(ctx context.Context, userID uuid.UUID, holdID uuid.UUID) (*entities.Hold, error) {
+	hold, err := h.stor.VoidHold(ctx, userID, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("can't void hold: %w", err)
+	}
+	return hold, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Brute-force protection of login: progressive delays and lockout per login and per client IP.
+type LoginGuard struct {
+	stor   LoginRepo
+	policy entities.LoginPolicy
+}
+
+type LoginRepo interface {
+	TakeAttempt(ctx context.Context, keys []entities.AttemptKey, policy entities.LoginPolicy) (wait time.Duration, err error)
+	ResetAttempts(ctx context.Context, scope string, key string) error
+	RefundAttempt(ctx context.Context, scope string, key string) error
+	UnlockLogin(ctx context.Context, admin string, login string) error
+}
+
+func NewLoginGuard(stor LoginRepo, policy entities.LoginPolicy) *LoginGuard {
+	return &LoginGuard{stor: stor, policy: policy}
+}
+
+// Time to wait before attempt of login from IP, 0 - attempt allowed.
+// Allowed attempt is counted as failure before password is checked, so parallel guesses can't pass together.
+// Login is tracked whether it exists or not.
+func (g *LoginGuard) Attempt(ctx context.Context, login string, ip string) (time.Duration, error) {
+	return g.stor.TakeAttempt(ctx, g.policy.LoginKeys(login, ip), g.policy)
+}
+
+// Time to wait before password reset request of login from IP, 0 - request allowed.
+// Every request is counted, so reset can't be used to flood user with tokens or to probe logins.
+func (g *LoginGuard) AttemptReset(ctx context.Context, login string, ip string) (time.Duration, error) {
+	return g.stor.TakeAttempt(ctx, g.policy.ResetKeys(login, ip), g.policy)
+}
+
+// Reset failures of login after success, attempt is not counted for IP.
+func (g *LoginGuard) Success(ctx context.Context, login string, ip string) error {
+	err := g.stor.ResetAttempts(ctx, entities.AttemptLogin, entities.LoginKey(login))
+	if err != nil {
+		return err
+	}
+	return g.stor.RefundAttempt(ctx, entities.AttemptIP, ip)
+}
+
+func (g *LoginGuard) Unlock(ctx context.Context, admin string, login string) error {
+	return g.stor.UnlockLogin(ctx, admin, entities.LoginKey(login))
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Length of key's prefix shown to admins, with "gm_".
+const keyPrefixLength = 11
+
+// API keys of merchants' servers.
+type MerchantService struct {
+	stor MerchantRepo
+}
+
+type MerchantRepo interface {
+	AddMerchantKey(ctx context.Context, key *entities.MerchantKey) error
+	GetMerchantKeys(ctx context.Context) ([]entities.MerchantKey, error)
+	RevokeMerchantKey(ctx context.Context, admin string, keyID uuid.UUID) error
+	UseMerchantKey(ctx context.Context, keyHash string) (*entities.MerchantKey, error)
+	FindUser(ctx context.Context, login string) (*entities.UserInfo, error)
+	GetMerchantOrder(ctx context.Context, key *entities.MerchantKey, userID uuid.UUID, order string) (*entities.Order, error)
+	GetOrderHistory(ctx context.Context, userID uuid.UUID, order string) ([]entities.StatusChange, error)
+}
+
+func NewMerchantService(stor MerchantRepo) *MerchantService {
+	return &MerchantService{stor: stor}
+}
+
+// Create key, the key is returned only here.
+func (s *MerchantService) Issue(ctx context.Context, key *entities.MerchantKey) (*entities.IssuedKey, error) {
+	token, _, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	secret := entities.MerchantKeyPrefix + token
+	key.Prefix = secret[:keyPrefixLength]
+	key.KeyHash = hashToken(secret)
+
+	err = s.stor.AddMerchantKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.IssuedKey{Key: secret, MerchantKey: key}, nil
+}
+
+func (s *MerchantService) GetKeys(ctx context.Context) ([]entities.MerchantKey, error) {
+	return s.stor.GetMerchantKeys(ctx)
+}
+
+func (s *MerchantService) Revoke(ctx context.Context, admin string, keyID uuid.UUID) error {
+	return s.stor.RevokeMerchantKey(ctx, admin, keyID)
+}
+
+// Active key of request, not revoked and not expired.
+func (s *MerchantService) Authenticate(ctx context.Context, secret string) (*entities.MerchantKey, error) {
+	if !strings.HasPrefix(secret, entities.MerchantKeyPrefix) {
+		return nil, entities.ErrKeyNotFound
+	}
+	return s.stor.UseMerchantKey(ctx, hashToken(secret))
+}
+
+// User of merchant's upload.
+func (s *MerchantService) FindUser(ctx context.Context, login string) (*entities.UserInfo, error) {
+	return s.stor.FindUser(ctx, entities.NormalizeLogin(login))
+}
+
+// User's order uploaded by the key with status history, entities.ErrOrderNotFound for other orders.
+func (s *MerchantService) GetOrder(ctx context.Context, key *entities.MerchantKey, userID uuid.UUID, orderNr string) (*entities.OrderDetail, error) {
+	order, err := s.stor.GetMerchantOrder(ctx, key, userID, orderNr)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.stor.GetOrderHistory(ctx, userID, orderNr)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.OrderDetail{Order: order, History: history}, nil
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
@@ -38,17 +39,32 @@ func (m *MockAccrualRepo) EXPECT() *MockAccrualRepoMockRecorder {
 }
 
 // AddBonuses mocks base method.
-func (m *MockAccrualRepo) AddBonuses(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+func (m *MockAccrualRepo) AddBonuses(ctx context.Context, entry *entities.LedgerEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddBonuses", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "AddBonuses", ctx, entry)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddBonuses indicates an expected call of AddBonuses.
-func (mr *MockAccrualRepoMockRecorder) AddBonuses(ctx, userID, amount interface{}) *gomock.Call {
+func (mr *MockAccrualRepoMockRecorder) AddBonuses(ctx, entry interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBonuses", reflect.TypeOf((*MockAccrualRepo)(nil).AddBonuses), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBonuses", reflect.TypeOf((*MockAccrualRepo)(nil).AddBonuses), ctx, entry)
+}
+
+// Clawback mocks base method.
+func (m *MockAccrualRepo) Clawback(ctx context.Context, order string, accrual decimal.Decimal) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clawback", ctx, order, accrual)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Clawback indicates an expected call of Clawback.
+func (mr *MockAccrualRepoMockRecorder) Clawback(ctx, order, accrual interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clawback", reflect.TypeOf((*MockAccrualRepo)(nil).Clawback), ctx, order, accrual)
 }
 
 // LoadPocessing mocks base method.
@@ -66,32 +82,34 @@ func (mr *MockAccrualRepoMockRecorder) LoadPocessing(ctx interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPocessing", reflect.TypeOf((*MockAccrualRepo)(nil).LoadPocessing), ctx)
 }
 
-// SetAccrual mocks base method.
-func (m *MockAccrualRepo) SetAccrual(ctx context.Context, order string, accrual decimal.Decimal) error {
+// LoadReturnable mocks base method.
+func (m *MockAccrualRepo) LoadReturnable(ctx context.Context, since time.Time) ([]entities.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetAccrual", ctx, order, accrual)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "LoadReturnable", ctx, since)
+	ret0, _ := ret[0].([]entities.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SetAccrual indicates an expected call of SetAccrual.
-func (mr *MockAccrualRepoMockRecorder) SetAccrual(ctx, order, accrual interface{}) *gomock.Call {
+// LoadReturnable indicates an expected call of LoadReturnable.
+func (mr *MockAccrualRepoMockRecorder) LoadReturnable(ctx, since interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccrual", reflect.TypeOf((*MockAccrualRepo)(nil).SetAccrual), ctx, order, accrual)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadReturnable", reflect.TypeOf((*MockAccrualRepo)(nil).LoadReturnable), ctx, since)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockAccrualRepo) UpdateStatus(ctx context.Context, order string, status entities.Status) error {
+func (m *MockAccrualRepo) UpdateStatus(ctx context.Context, order string, status entities.Status, accrual decimal.Decimal) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", ctx, order, status)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, order, status, accrual)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockAccrualRepoMockRecorder) UpdateStatus(ctx, order, status interface{}) *gomock.Call {
+func (mr *MockAccrualRepoMockRecorder) UpdateStatus(ctx, order, status, accrual interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAccrualRepo)(nil).UpdateStatus), ctx, order, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAccrualRepo)(nil).UpdateStatus), ctx, order, status, accrual)
 }
 
 // MockAccrualClient is a mock of AccrualClient interface.
@@ -131,3 +149,17 @@ func (mr *MockAccrualClientMockRecorder) GetOrderStatus(orderNr interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatus", reflect.TypeOf((*MockAccrualClient)(nil).GetOrderStatus), orderNr)
 }
+
+// RegisterOrder mocks base method.
+func (m *MockAccrualClient) RegisterOrder(orderNr string, goods []entities.Good) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOrder", orderNr, goods)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterOrder indicates an expected call of RegisterOrder.
+func (mr *MockAccrualClientMockRecorder) RegisterOrder(orderNr, goods interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOrder", reflect.TypeOf((*MockAccrualClient)(nil).RegisterOrder), orderNr, goods)
+}
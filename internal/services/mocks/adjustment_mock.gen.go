@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/adjustment.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockAdjustmentRepo is a mock of AdjustmentRepo interface.
+type MockAdjustmentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdjustmentRepoMockRecorder
+}
+
+// MockAdjustmentRepoMockRecorder is the mock recorder for MockAdjustmentRepo.
+type MockAdjustmentRepoMockRecorder struct {
+	mock *MockAdjustmentRepo
+}
+
+// NewMockAdjustmentRepo creates a new mock instance.
+func NewMockAdjustmentRepo(ctrl *gomock.Controller) *MockAdjustmentRepo {
+	mock := &MockAdjustmentRepo{ctrl: ctrl}
+	mock.recorder = &MockAdjustmentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdjustmentRepo) EXPECT() *MockAdjustmentRepoMockRecorder {
+	return m.recorder
+}
+
+// AddAdjustment mocks base method.
+func (m *MockAdjustmentRepo) AddAdjustment(ctx context.Context, adj *entities.Adjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdjustment", ctx, adj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAdjustment indicates an expected call of AddAdjustment.
+func (mr *MockAdjustmentRepoMockRecorder) AddAdjustment(ctx, adj interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdjustment", reflect.TypeOf((*MockAdjustmentRepo)(nil).AddAdjustment), ctx, adj)
+}
+
+// DecideAdjustment mocks base method.
+func (m *MockAdjustmentRepo) DecideAdjustment(ctx context.Context, adjustmentID uuid.UUID, admin string, approve bool) (*entities.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideAdjustment", ctx, adjustmentID, admin, approve)
+	ret0, _ := ret[0].(*entities.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideAdjustment indicates an expected call of DecideAdjustment.
+func (mr *MockAdjustmentRepoMockRecorder) DecideAdjustment(ctx, adjustmentID, admin, approve interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideAdjustment", reflect.TypeOf((*MockAdjustmentRepo)(nil).DecideAdjustment), ctx, adjustmentID, admin, approve)
+}
+
+// GetAdjustments mocks base method.
+func (m *MockAdjustmentRepo) GetAdjustments(ctx context.Context, status entities.AdjustmentStatus) ([]entities.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", ctx, status)
+	ret0, _ := ret[0].([]entities.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockAdjustmentRepoMockRecorder) GetAdjustments(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockAdjustmentRepo)(nil).GetAdjustments), ctx, status)
+}
+
+// GetAudit mocks base method.
+func (m *MockAdjustmentRepo) GetAudit(ctx context.Context, userID *uuid.UUID) ([]entities.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudit", ctx, userID)
+	ret0, _ := ret[0].([]entities.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudit indicates an expected call of GetAudit.
+func (mr *MockAdjustmentRepoMockRecorder) GetAudit(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudit", reflect.TypeOf((*MockAdjustmentRepo)(nil).GetAudit), ctx, userID)
+}
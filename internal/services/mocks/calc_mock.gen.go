@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
@@ -37,6 +38,23 @@ func (m *MockCalcRepo) EXPECT() *MockCalcRepoMockRecorder {
 	return m.recorder
 }
 
+// GetBalanceAt mocks base method.
+func (m *MockCalcRepo) GetBalanceAt(ctx context.Context, userID uuid.UUID, at time.Time) (decimal.Decimal, decimal.Decimal, decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", ctx, userID, at)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(decimal.Decimal)
+	ret2, _ := ret[2].(decimal.Decimal)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockCalcRepoMockRecorder) GetBalanceAt(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockCalcRepo)(nil).GetBalanceAt), ctx, userID, at)
+}
+
 // GetBonuses mocks base method.
 func (m *MockCalcRepo) GetBonuses(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
 	m.ctrl.T.Helper()
@@ -52,6 +70,51 @@ func (mr *MockCalcRepoMockRecorder) GetBonuses(ctx, userID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonuses", reflect.TypeOf((*MockCalcRepo)(nil).GetBonuses), ctx, userID)
 }
 
+// GetHeld mocks base method.
+func (m *MockCalcRepo) GetHeld(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeld", ctx, userID)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeld indicates an expected call of GetHeld.
+func (mr *MockCalcRepoMockRecorder) GetHeld(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeld", reflect.TypeOf((*MockCalcRepo)(nil).GetHeld), ctx, userID)
+}
+
+// GetHistory mocks base method.
+func (m *MockCalcRepo) GetHistory(ctx context.Context, userID uuid.UUID) ([]entities.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, userID)
+	ret0, _ := ret[0].([]entities.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockCalcRepoMockRecorder) GetHistory(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockCalcRepo)(nil).GetHistory), ctx, userID)
+}
+
+// GetRegistered mocks base method.
+func (m *MockCalcRepo) GetRegistered(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistered", ctx, userID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistered indicates an expected call of GetRegistered.
+func (mr *MockCalcRepoMockRecorder) GetRegistered(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistered", reflect.TypeOf((*MockCalcRepo)(nil).GetRegistered), ctx, userID)
+}
+
 // GetWithdrawals mocks base method.
 func (m *MockCalcRepo) GetWithdrawals(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
 	m.ctrl.T.Helper()
@@ -82,33 +145,18 @@ func (mr *MockCalcRepoMockRecorder) GetWithdrawn(ctx, userID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawn", reflect.TypeOf((*MockCalcRepo)(nil).GetWithdrawn), ctx, userID)
 }
 
-// IsPreOrder mocks base method.
-func (m *MockCalcRepo) IsPreOrder(ctx context.Context, userID uuid.UUID, order string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsPreOrder", ctx, userID, order)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// IsPreOrder indicates an expected call of IsPreOrder.
-func (mr *MockCalcRepoMockRecorder) IsPreOrder(ctx, userID, order interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPreOrder", reflect.TypeOf((*MockCalcRepo)(nil).IsPreOrder), ctx, userID, order)
-}
-
 // MakeWithdrawn mocks base method.
-func (m *MockCalcRepo) MakeWithdrawn(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+func (m *MockCalcRepo) MakeWithdrawn(ctx context.Context, userID uuid.UUID, order string, amount decimal.Decimal, limits entities.WithdrawLimits) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MakeWithdrawn", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "MakeWithdrawn", ctx, userID, order, amount, limits)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MakeWithdrawn indicates an expected call of MakeWithdrawn.
-func (mr *MockCalcRepoMockRecorder) MakeWithdrawn(ctx, userID, amount interface{}) *gomock.Call {
+func (mr *MockCalcRepoMockRecorder) MakeWithdrawn(ctx, userID, order, amount, limits interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeWithdrawn", reflect.TypeOf((*MockCalcRepo)(nil).MakeWithdrawn), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeWithdrawn", reflect.TypeOf((*MockCalcRepo)(nil).MakeWithdrawn), ctx, userID, order, amount, limits)
 }
 
 // MovePreOrder mocks base method.
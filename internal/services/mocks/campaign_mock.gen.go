@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/campaign.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockCampaignRepo is a mock of CampaignRepo interface.
+type MockCampaignRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCampaignRepoMockRecorder
+}
+
+// MockCampaignRepoMockRecorder is the mock recorder for MockCampaignRepo.
+type MockCampaignRepoMockRecorder struct {
+	mock *MockCampaignRepo
+}
+
+// NewMockCampaignRepo creates a new mock instance.
+func NewMockCampaignRepo(ctrl *gomock.Controller) *MockCampaignRepo {
+	mock := &MockCampaignRepo{ctrl: ctrl}
+	mock.recorder = &MockCampaignRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCampaignRepo) EXPECT() *MockCampaignRepoMockRecorder {
+	return m.recorder
+}
+
+// AddCampaign mocks base method.
+func (m *MockCampaignRepo) AddCampaign(ctx context.Context, campaign *entities.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCampaign", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCampaign indicates an expected call of AddCampaign.
+func (mr *MockCampaignRepoMockRecorder) AddCampaign(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCampaign", reflect.TypeOf((*MockCampaignRepo)(nil).AddCampaign), ctx, campaign)
+}
+
+// CountProcessed mocks base method.
+func (m *MockCampaignRepo) CountProcessed(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProcessed", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProcessed indicates an expected call of CountProcessed.
+func (mr *MockCampaignRepoMockRecorder) CountProcessed(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProcessed", reflect.TypeOf((*MockCampaignRepo)(nil).CountProcessed), ctx, userID)
+}
+
+// CreditCampaign mocks base method.
+func (m *MockCampaignRepo) CreditCampaign(ctx context.Context, campaignID uuid.UUID, entry *entities.LedgerEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditCampaign", ctx, campaignID, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreditCampaign indicates an expected call of CreditCampaign.
+func (mr *MockCampaignRepoMockRecorder) CreditCampaign(ctx, campaignID, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditCampaign", reflect.TypeOf((*MockCampaignRepo)(nil).CreditCampaign), ctx, campaignID, entry)
+}
+
+// GetActiveCampaigns mocks base method.
+func (m *MockCampaignRepo) GetActiveCampaigns(ctx context.Context, at time.Time) ([]entities.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveCampaigns", ctx, at)
+	ret0, _ := ret[0].([]entities.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveCampaigns indicates an expected call of GetActiveCampaigns.
+func (mr *MockCampaignRepoMockRecorder) GetActiveCampaigns(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveCampaigns", reflect.TypeOf((*MockCampaignRepo)(nil).GetActiveCampaigns), ctx, at)
+}
+
+// GetCampaignReport mocks base method.
+func (m *MockCampaignRepo) GetCampaignReport(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignReport", ctx, campaignID)
+	ret0, _ := ret[0].(*entities.CampaignReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignReport indicates an expected call of GetCampaignReport.
+func (mr *MockCampaignRepoMockRecorder) GetCampaignReport(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignReport", reflect.TypeOf((*MockCampaignRepo)(nil).GetCampaignReport), ctx, campaignID)
+}
+
+// GetCampaigns mocks base method.
+func (m *MockCampaignRepo) GetCampaigns(ctx context.Context) ([]entities.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaigns", ctx)
+	ret0, _ := ret[0].([]entities.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaigns indicates an expected call of GetCampaigns.
+func (mr *MockCampaignRepoMockRecorder) GetCampaigns(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaigns", reflect.TypeOf((*MockCampaignRepo)(nil).GetCampaigns), ctx)
+}
+
+// SetCampaignPaused mocks base method.
+func (m *MockCampaignRepo) SetCampaignPaused(ctx context.Context, campaignID uuid.UUID, paused bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCampaignPaused", ctx, campaignID, paused)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCampaignPaused indicates an expected call of SetCampaignPaused.
+func (mr *MockCampaignRepoMockRecorder) SetCampaignPaused(ctx, campaignID, paused interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCampaignPaused", reflect.TypeOf((*MockCampaignRepo)(nil).SetCampaignPaused), ctx, campaignID, paused)
+}
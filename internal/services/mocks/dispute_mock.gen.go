@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/dispute.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockDisputeRepo is a mock of DisputeRepo interface.
+type MockDisputeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisputeRepoMockRecorder
+}
+
+// MockDisputeRepoMockRecorder is the mock recorder for MockDisputeRepo.
+type MockDisputeRepoMockRecorder struct {
+	mock *MockDisputeRepo
+}
+
+// NewMockDisputeRepo creates a new mock instance.
+func NewMockDisputeRepo(ctrl *gomock.Controller) *MockDisputeRepo {
+	mock := &MockDisputeRepo{ctrl: ctrl}
+	mock.recorder = &MockDisputeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisputeRepo) EXPECT() *MockDisputeRepoMockRecorder {
+	return m.recorder
+}
+
+// AddDispute mocks base method.
+func (m *MockDisputeRepo) AddDispute(ctx context.Context, dispute *entities.Dispute) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDispute", ctx, dispute)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDispute indicates an expected call of AddDispute.
+func (mr *MockDisputeRepoMockRecorder) AddDispute(ctx, dispute interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDispute", reflect.TypeOf((*MockDisputeRepo)(nil).AddDispute), ctx, dispute)
+}
+
+// DecideDispute mocks base method.
+func (m *MockDisputeRepo) DecideDispute(ctx context.Context, disputeID uuid.UUID, admin string, reassign bool, note string) (*entities.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideDispute", ctx, disputeID, admin, reassign, note)
+	ret0, _ := ret[0].(*entities.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideDispute indicates an expected call of DecideDispute.
+func (mr *MockDisputeRepoMockRecorder) DecideDispute(ctx, disputeID, admin, reassign, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideDispute", reflect.TypeOf((*MockDisputeRepo)(nil).DecideDispute), ctx, disputeID, admin, reassign, note)
+}
+
+// GetDisputes mocks base method.
+func (m *MockDisputeRepo) GetDisputes(ctx context.Context, claimant *uuid.UUID, status entities.DisputeStatus) ([]entities.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputes", ctx, claimant, status)
+	ret0, _ := ret[0].([]entities.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputes indicates an expected call of GetDisputes.
+func (mr *MockDisputeRepoMockRecorder) GetDisputes(ctx, claimant, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputes", reflect.TypeOf((*MockDisputeRepo)(nil).GetDisputes), ctx, claimant, status)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/holds.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockHoldRepo is a mock of HoldRepo interface.
+type MockHoldRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldRepoMockRecorder
+}
+
+// MockHoldRepoMockRecorder is the mock recorder for MockHoldRepo.
+type MockHoldRepoMockRecorder struct {
+	mock *MockHoldRepo
+}
+
+// NewMockHoldRepo creates a new mock instance.
+func NewMockHoldRepo(ctrl *gomock.Controller) *MockHoldRepo {
+	mock := &MockHoldRepo{ctrl: ctrl}
+	mock.recorder = &MockHoldRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldRepo) EXPECT() *MockHoldRepoMockRecorder {
+	return m.recorder
+}
+
+// CaptureHold mocks base method.
+func (m *MockHoldRepo) CaptureHold(ctx context.Context, userID, holdID uuid.UUID, order string) (*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, userID, holdID, order)
+	ret0, _ := ret[0].(*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockHoldRepoMockRecorder) CaptureHold(ctx, userID, holdID, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockHoldRepo)(nil).CaptureHold), ctx, userID, holdID, order)
+}
+
+// CreateHold mocks base method.
+func (m *MockHoldRepo) CreateHold(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, expires time.Time, limits entities.WithdrawLimits) (*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, userID, amount, expires, limits)
+	ret0, _ := ret[0].(*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockHoldRepoMockRecorder) CreateHold(ctx, userID, amount, expires, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockHoldRepo)(nil).CreateHold), ctx, userID, amount, expires, limits)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockHoldRepo) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockHoldRepoMockRecorder) ExpireHolds(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockHoldRepo)(nil).ExpireHolds), ctx, now)
+}
+
+// GetHolds mocks base method.
+func (m *MockHoldRepo) GetHolds(ctx context.Context, userID uuid.UUID) ([]entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHolds", ctx, userID)
+	ret0, _ := ret[0].([]entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHolds indicates an expected call of GetHolds.
+func (mr *MockHoldRepoMockRecorder) GetHolds(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHolds", reflect.TypeOf((*MockHoldRepo)(nil).GetHolds), ctx, userID)
+}
+
+// VoidHold mocks base method.
+func (m *MockHoldRepo) VoidHold(ctx context.Context, userID, holdID uuid.UUID) (*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, userID, holdID)
+	ret0, _ := ret[0].(*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockHoldRepoMockRecorder) VoidHold(ctx, userID, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockHoldRepo)(nil).VoidHold), ctx, userID, holdID)
+}
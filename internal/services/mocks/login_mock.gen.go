@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/login.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockLoginRepo is a mock of LoginRepo interface.
+type MockLoginRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginRepoMockRecorder
+}
+
+// MockLoginRepoMockRecorder is the mock recorder for MockLoginRepo.
+type MockLoginRepoMockRecorder struct {
+	mock *MockLoginRepo
+}
+
+// NewMockLoginRepo creates a new mock instance.
+func NewMockLoginRepo(ctrl *gomock.Controller) *MockLoginRepo {
+	mock := &MockLoginRepo{ctrl: ctrl}
+	mock.recorder = &MockLoginRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginRepo) EXPECT() *MockLoginRepoMockRecorder {
+	return m.recorder
+}
+
+// RefundAttempt mocks base method.
+func (m *MockLoginRepo) RefundAttempt(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundAttempt", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundAttempt indicates an expected call of RefundAttempt.
+func (mr *MockLoginRepoMockRecorder) RefundAttempt(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundAttempt", reflect.TypeOf((*MockLoginRepo)(nil).RefundAttempt), ctx, scope, key)
+}
+
+// ResetAttempts mocks base method.
+func (m *MockLoginRepo) ResetAttempts(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAttempts", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAttempts indicates an expected call of ResetAttempts.
+func (mr *MockLoginRepoMockRecorder) ResetAttempts(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAttempts", reflect.TypeOf((*MockLoginRepo)(nil).ResetAttempts), ctx, scope, key)
+}
+
+// TakeAttempt mocks base method.
+func (m *MockLoginRepo) TakeAttempt(ctx context.Context, keys []entities.AttemptKey, policy entities.LoginPolicy) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeAttempt", ctx, keys, policy)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeAttempt indicates an expected call of TakeAttempt.
+func (mr *MockLoginRepoMockRecorder) TakeAttempt(ctx, keys, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeAttempt", reflect.TypeOf((*MockLoginRepo)(nil).TakeAttempt), ctx, keys, policy)
+}
+
+// UnlockLogin mocks base method.
+func (m *MockLoginRepo) UnlockLogin(ctx context.Context, admin, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockLogin", ctx, admin, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockLogin indicates an expected call of UnlockLogin.
+func (mr *MockLoginRepoMockRecorder) UnlockLogin(ctx, admin, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockLogin", reflect.TypeOf((*MockLoginRepo)(nil).UnlockLogin), ctx, admin, login)
+}
@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/merchant.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockMerchantRepo is a mock of MerchantRepo interface.
+type MockMerchantRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchantRepoMockRecorder
+}
+
+// MockMerchantRepoMockRecorder is the mock recorder for MockMerchantRepo.
+type MockMerchantRepoMockRecorder struct {
+	mock *MockMerchantRepo
+}
+
+// NewMockMerchantRepo creates a new mock instance.
+func NewMockMerchantRepo(ctrl *gomock.Controller) *MockMerchantRepo {
+	mock := &MockMerchantRepo{ctrl: ctrl}
+	mock.recorder = &MockMerchantRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchantRepo) EXPECT() *MockMerchantRepoMockRecorder {
+	return m.recorder
+}
+
+// AddMerchantKey mocks base method.
+func (m *MockMerchantRepo) AddMerchantKey(ctx context.Context, key *entities.MerchantKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMerchantKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMerchantKey indicates an expected call of AddMerchantKey.
+func (mr *MockMerchantRepoMockRecorder) AddMerchantKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMerchantKey", reflect.TypeOf((*MockMerchantRepo)(nil).AddMerchantKey), ctx, key)
+}
+
+// FindUser mocks base method.
+func (m *MockMerchantRepo) FindUser(ctx context.Context, login string) (*entities.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUser", ctx, login)
+	ret0, _ := ret[0].(*entities.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUser indicates an expected call of FindUser.
+func (mr *MockMerchantRepoMockRecorder) FindUser(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUser", reflect.TypeOf((*MockMerchantRepo)(nil).FindUser), ctx, login)
+}
+
+// GetMerchantKeys mocks base method.
+func (m *MockMerchantRepo) GetMerchantKeys(ctx context.Context) ([]entities.MerchantKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantKeys", ctx)
+	ret0, _ := ret[0].([]entities.MerchantKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantKeys indicates an expected call of GetMerchantKeys.
+func (mr *MockMerchantRepoMockRecorder) GetMerchantKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantKeys", reflect.TypeOf((*MockMerchantRepo)(nil).GetMerchantKeys), ctx)
+}
+
+// GetMerchantOrder mocks base method.
+func (m *MockMerchantRepo) GetMerchantOrder(ctx context.Context, key *entities.MerchantKey, userID uuid.UUID, order string) (*entities.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantOrder", ctx, key, userID, order)
+	ret0, _ := ret[0].(*entities.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantOrder indicates an expected call of GetMerchantOrder.
+func (mr *MockMerchantRepoMockRecorder) GetMerchantOrder(ctx, key, userID, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantOrder", reflect.TypeOf((*MockMerchantRepo)(nil).GetMerchantOrder), ctx, key, userID, order)
+}
+
+// GetOrderHistory mocks base method.
+func (m *MockMerchantRepo) GetOrderHistory(ctx context.Context, userID uuid.UUID, order string) ([]entities.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderHistory", ctx, userID, order)
+	ret0, _ := ret[0].([]entities.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderHistory indicates an expected call of GetOrderHistory.
+func (mr *MockMerchantRepoMockRecorder) GetOrderHistory(ctx, userID, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockMerchantRepo)(nil).GetOrderHistory), ctx, userID, order)
+}
+
+// RevokeMerchantKey mocks base method.
+func (m *MockMerchantRepo) RevokeMerchantKey(ctx context.Context, admin string, keyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeMerchantKey", ctx, admin, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeMerchantKey indicates an expected call of RevokeMerchantKey.
+func (mr *MockMerchantRepoMockRecorder) RevokeMerchantKey(ctx, admin, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeMerchantKey", reflect.TypeOf((*MockMerchantRepo)(nil).RevokeMerchantKey), ctx, admin, keyID)
+}
+
+// UseMerchantKey mocks base method.
+func (m *MockMerchantRepo) UseMerchantKey(ctx context.Context, keyHash string) (*entities.MerchantKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMerchantKey", ctx, keyHash)
+	ret0, _ := ret[0].(*entities.MerchantKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseMerchantKey indicates an expected call of UseMerchantKey.
+func (mr *MockMerchantRepoMockRecorder) UseMerchantKey(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMerchantKey", reflect.TypeOf((*MockMerchantRepo)(nil).UseMerchantKey), ctx, keyHash)
+}
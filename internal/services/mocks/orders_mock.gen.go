@@ -37,11 +37,12 @@ func (m *MockOrderRepo) EXPECT() *MockOrderRepoMockRecorder {
 }
 
 // AddOrder mocks base method.
-func (m *MockOrderRepo) AddOrder(ctx context.Context, data *entities.AddOrder) error {
+func (m *MockOrderRepo) AddOrder(ctx context.Context, data *entities.AddOrder) (*entities.BatchOrder, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddOrder", ctx, data)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entities.BatchOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddOrder indicates an expected call of AddOrder.
@@ -50,19 +51,78 @@ func (mr *MockOrderRepoMockRecorder) AddOrder(ctx, data interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrder", reflect.TypeOf((*MockOrderRepo)(nil).AddOrder), ctx, data)
 }
 
+// AddOrders mocks base method.
+func (m *MockOrderRepo) AddOrders(ctx context.Context, userID uuid.UUID, orders []string) ([]entities.BatchOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrders", ctx, userID, orders)
+	ret0, _ := ret[0].([]entities.BatchOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrders indicates an expected call of AddOrders.
+func (mr *MockOrderRepoMockRecorder) AddOrders(ctx, userID, orders interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrders", reflect.TypeOf((*MockOrderRepo)(nil).AddOrders), ctx, userID, orders)
+}
+
+// DeleteOrder mocks base method.
+func (m *MockOrderRepo) DeleteOrder(ctx context.Context, userID uuid.UUID, order string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrder", ctx, userID, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrder indicates an expected call of DeleteOrder.
+func (mr *MockOrderRepoMockRecorder) DeleteOrder(ctx, userID, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrderRepo)(nil).DeleteOrder), ctx, userID, order)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderRepo) GetOrder(ctx context.Context, userID uuid.UUID, order string) (*entities.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, userID, order)
+	ret0, _ := ret[0].(*entities.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderRepoMockRecorder) GetOrder(ctx, userID, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepo)(nil).GetOrder), ctx, userID, order)
+}
+
+// GetOrderHistory mocks base method.
+func (m *MockOrderRepo) GetOrderHistory(ctx context.Context, userID uuid.UUID, order string) ([]entities.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderHistory", ctx, userID, order)
+	ret0, _ := ret[0].([]entities.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderHistory indicates an expected call of GetOrderHistory.
+func (mr *MockOrderRepoMockRecorder) GetOrderHistory(ctx, userID, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockOrderRepo)(nil).GetOrderHistory), ctx, userID, order)
+}
+
 // GetOrders mocks base method.
-func (m *MockOrderRepo) GetOrders(ctx context.Context, userID uuid.UUID) ([]entities.Order, error) {
+func (m *MockOrderRepo) GetOrders(ctx context.Context, userID uuid.UUID, filter *entities.OrderFilter) ([]entities.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrders", ctx, userID)
+	ret := m.ctrl.Call(m, "GetOrders", ctx, userID, filter)
 	ret0, _ := ret[0].([]entities.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrders indicates an expected call of GetOrders.
-func (mr *MockOrderRepoMockRecorder) GetOrders(ctx, userID interface{}) *gomock.Call {
+func (mr *MockOrderRepoMockRecorder) GetOrders(ctx, userID, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderRepo)(nil).GetOrders), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderRepo)(nil).GetOrders), ctx, userID, filter)
 }
 
 // IsExist mocks base method.
@@ -79,18 +139,3 @@ func (mr *MockOrderRepoMockRecorder) IsExist(ctx, order interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExist", reflect.TypeOf((*MockOrderRepo)(nil).IsExist), ctx, order)
 }
-
-// IsExistForOtherUser mocks base method.
-func (m *MockOrderRepo) IsExistForOtherUser(ctx context.Context, userID uuid.UUID, order string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsExistForOtherUser", ctx, userID, order)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// IsExistForOtherUser indicates an expected call of IsExistForOtherUser.
-func (mr *MockOrderRepoMockRecorder) IsExistForOtherUser(ctx, userID, order interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExistForOtherUser", reflect.TypeOf((*MockOrderRepo)(nil).IsExistForOtherUser), ctx, userID, order)
-}
@@ -0,0 +1,148 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/password.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockPasswordRepo is a mock of PasswordRepo interface.
+type MockPasswordRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordRepoMockRecorder
+}
+
+// MockPasswordRepoMockRecorder is the mock recorder for MockPasswordRepo.
+type MockPasswordRepoMockRecorder struct {
+	mock *MockPasswordRepo
+}
+
+// NewMockPasswordRepo creates a new mock instance.
+func NewMockPasswordRepo(ctrl *gomock.Controller) *MockPasswordRepo {
+	mock := &MockPasswordRepo{ctrl: ctrl}
+	mock.recorder = &MockPasswordRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordRepo) EXPECT() *MockPasswordRepoMockRecorder {
+	return m.recorder
+}
+
+// AddPasswordReset mocks base method.
+func (m *MockPasswordRepo) AddPasswordReset(ctx context.Context, reset *entities.PasswordReset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPasswordReset", ctx, reset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPasswordReset indicates an expected call of AddPasswordReset.
+func (mr *MockPasswordRepoMockRecorder) AddPasswordReset(ctx, reset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPasswordReset", reflect.TypeOf((*MockPasswordRepo)(nil).AddPasswordReset), ctx, reset)
+}
+
+// GetByLogin mocks base method.
+func (m *MockPasswordRepo) GetByLogin(ctx context.Context, login string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLogin", ctx, login)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLogin indicates an expected call of GetByLogin.
+func (mr *MockPasswordRepoMockRecorder) GetByLogin(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLogin", reflect.TypeOf((*MockPasswordRepo)(nil).GetByLogin), ctx, login)
+}
+
+// GetPasswordHash mocks base method.
+func (m *MockPasswordRepo) GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordHash", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordHash indicates an expected call of GetPasswordHash.
+func (mr *MockPasswordRepoMockRecorder) GetPasswordHash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordHash", reflect.TypeOf((*MockPasswordRepo)(nil).GetPasswordHash), ctx, userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockPasswordRepo) ResetPassword(ctx context.Context, tokenHash, hash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, hash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockPasswordRepoMockRecorder) ResetPassword(ctx, tokenHash, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockPasswordRepo)(nil).ResetPassword), ctx, tokenHash, hash)
+}
+
+// SetPassword mocks base method.
+func (m *MockPasswordRepo) SetPassword(ctx context.Context, userID uuid.UUID, hash string, keep uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", ctx, userID, hash, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockPasswordRepoMockRecorder) SetPassword(ctx, userID, hash, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockPasswordRepo)(nil).SetPassword), ctx, userID, hash, keep)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// SendPasswordReset mocks base method.
+func (m *MockNotifier) SendPasswordReset(ctx context.Context, login, token string, expires time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPasswordReset", ctx, login, token, expires)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPasswordReset indicates an expected call of SendPasswordReset.
+func (mr *MockNotifierMockRecorder) SendPasswordReset(ctx, login, token, expires interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordReset", reflect.TypeOf((*MockNotifier)(nil).SendPasswordReset), ctx, login, token, expires)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/referral.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockReferralRepo is a mock of ReferralRepo interface.
+type MockReferralRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralRepoMockRecorder
+}
+
+// MockReferralRepoMockRecorder is the mock recorder for MockReferralRepo.
+type MockReferralRepoMockRecorder struct {
+	mock *MockReferralRepo
+}
+
+// NewMockReferralRepo creates a new mock instance.
+func NewMockReferralRepo(ctrl *gomock.Controller) *MockReferralRepo {
+	mock := &MockReferralRepo{ctrl: ctrl}
+	mock.recorder = &MockReferralRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralRepo) EXPECT() *MockReferralRepoMockRecorder {
+	return m.recorder
+}
+
+// AddReferral mocks base method.
+func (m *MockReferralRepo) AddReferral(ctx context.Context, referrerID, refereeID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReferral", ctx, referrerID, refereeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReferral indicates an expected call of AddReferral.
+func (mr *MockReferralRepoMockRecorder) AddReferral(ctx, referrerID, refereeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReferral", reflect.TypeOf((*MockReferralRepo)(nil).AddReferral), ctx, referrerID, refereeID)
+}
+
+// GetByReferralCode mocks base method.
+func (m *MockReferralRepo) GetByReferralCode(ctx context.Context, code string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByReferralCode", ctx, code)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByReferralCode indicates an expected call of GetByReferralCode.
+func (mr *MockReferralRepoMockRecorder) GetByReferralCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReferralCode", reflect.TypeOf((*MockReferralRepo)(nil).GetByReferralCode), ctx, code)
+}
+
+// GetReferralCode mocks base method.
+func (m *MockReferralRepo) GetReferralCode(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralCode", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralCode indicates an expected call of GetReferralCode.
+func (mr *MockReferralRepoMockRecorder) GetReferralCode(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralCode", reflect.TypeOf((*MockReferralRepo)(nil).GetReferralCode), ctx, userID)
+}
+
+// GetReferrals mocks base method.
+func (m *MockReferralRepo) GetReferrals(ctx context.Context, referrerID uuid.UUID) ([]entities.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferrals", ctx, referrerID)
+	ret0, _ := ret[0].([]entities.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferrals indicates an expected call of GetReferrals.
+func (mr *MockReferralRepoMockRecorder) GetReferrals(ctx, referrerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferrals", reflect.TypeOf((*MockReferralRepo)(nil).GetReferrals), ctx, referrerID)
+}
+
+// RewardReferral mocks base method.
+func (m *MockReferralRepo) RewardReferral(ctx context.Context, refereeID uuid.UUID, referrerBonus, refereeBonus decimal.Decimal, limit int) (*entities.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewardReferral", ctx, refereeID, referrerBonus, refereeBonus, limit)
+	ret0, _ := ret[0].(*entities.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewardReferral indicates an expected call of RewardReferral.
+func (mr *MockReferralRepoMockRecorder) RewardReferral(ctx, refereeID, referrerBonus, refereeBonus, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewardReferral", reflect.TypeOf((*MockReferralRepo)(nil).RewardReferral), ctx, refereeID, referrerBonus, refereeBonus, limit)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/tier.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+	entities "github.com/shulganew/gophermart/internal/entities"
+)
+
+// MockTierRepo is a mock of TierRepo interface.
+type MockTierRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTierRepoMockRecorder
+}
+
+// MockTierRepoMockRecorder is the mock recorder for MockTierRepo.
+type MockTierRepoMockRecorder struct {
+	mock *MockTierRepo
+}
+
+// NewMockTierRepo creates a new mock instance.
+func NewMockTierRepo(ctrl *gomock.Controller) *MockTierRepo {
+	mock := &MockTierRepo{ctrl: ctrl}
+	mock.recorder = &MockTierRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTierRepo) EXPECT() *MockTierRepoMockRecorder {
+	return m.recorder
+}
+
+// GetLifetimeAccruals mocks base method.
+func (m *MockTierRepo) GetLifetimeAccruals(ctx context.Context, userID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLifetimeAccruals", ctx, userID, since)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLifetimeAccruals indicates an expected call of GetLifetimeAccruals.
+func (mr *MockTierRepoMockRecorder) GetLifetimeAccruals(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLifetimeAccruals", reflect.TypeOf((*MockTierRepo)(nil).GetLifetimeAccruals), ctx, userID, since)
+}
+
+// GetTier mocks base method.
+func (m *MockTierRepo) GetTier(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTier", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTier indicates an expected call of GetTier.
+func (mr *MockTierRepoMockRecorder) GetTier(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTier", reflect.TypeOf((*MockTierRepo)(nil).GetTier), ctx, userID)
+}
+
+// GetTierHistory mocks base method.
+func (m *MockTierRepo) GetTierHistory(ctx context.Context, userID uuid.UUID) ([]entities.TierChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTierHistory", ctx, userID)
+	ret0, _ := ret[0].([]entities.TierChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTierHistory indicates an expected call of GetTierHistory.
+func (mr *MockTierRepoMockRecorder) GetTierHistory(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTierHistory", reflect.TypeOf((*MockTierRepo)(nil).GetTierHistory), ctx, userID)
+}
+
+// SetTier mocks base method.
+func (m *MockTierRepo) SetTier(ctx context.Context, change *entities.TierChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTier", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTier indicates an expected call of SetTier.
+func (mr *MockTierRepoMockRecorder) SetTier(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTier", reflect.TypeOf((*MockTierRepo)(nil).SetTier), ctx, change)
+}
@@ -6,54 +6,149 @@ import (
 	"fmt"
 
 	"github.com/gofrs/uuid"
-	"github.com/jackc/pgerrcode"
-	"github.com/lib/pq"
 	"github.com/shulganew/gophermart/internal/entities"
 )
 
 type OrderService struct {
-	stor OrderRepo
+	stor      OrderRepo
+	validator entities.OrderValidator
 }
 
 type OrderRepo interface {
-	AddOrder(ctx context.Context, data *entities.AddOrder) error
-	GetOrders(ctx context.Context, userID uuid.UUID) ([]entities.Order, error)
-	IsExistForOtherUser(ctx context.Context, userID uuid.UUID, order string) (isExist bool, err error)
+	AddOrder(ctx context.Context, data *entities.AddOrder) (*entities.BatchOrder, error)
+	GetOrders(ctx context.Context, userID uuid.UUID, filter *entities.OrderFilter) ([]entities.Order, error)
 	IsExist(ctx context.Context, order string) (isExist bool, err error)
+	AddOrders(ctx context.Context, userID uuid.UUID, orders []string) ([]entities.BatchOrder, error)
+	GetOrder(ctx context.Context, userID uuid.UUID, order string) (*entities.Order, error)
+	GetOrderHistory(ctx context.Context, userID uuid.UUID, order string) ([]entities.StatusChange, error)
+	DeleteOrder(ctx context.Context, userID uuid.UUID, order string) error
 }
 
-func NewOrderService(stor OrderRepo) *OrderService {
-	return &OrderService{stor: stor}
+func NewOrderService(stor OrderRepo, validator entities.OrderValidator) *OrderService {
+	return &OrderService{stor: stor, validator: validator}
 }
 
-func (m *OrderService) AddOrder(ctx context.Context, isPreOrder bool, order *entities.Order) (err error) {
-	// Add order to the database.
+// Check order number by rules of partner store, error has reason of rejection.
+func (m *OrderService) ValidateOrder(orderNr string) error {
+	return m.validator.Validate(orderNr)
+}
+
+// Add order to the database or report who owns existed order.
+func (m *OrderService) AddOrder(ctx context.Context, isPreOrder bool, order *entities.Order) (entities.UploadOutcome, error) {
 	addOrder := entities.NewAddOrder(order.UserID.String(), order.OrderNr, isPreOrder, order.Withdrawn)
-	err = m.stor.AddOrder(ctx, addOrder)
+	addOrder.Purchase = order.Purchase
+	addOrder.MerchantKey = order.MerchantKey
+	stored, err := m.stor.AddOrder(ctx, addOrder)
 	if err != nil {
-		var pgErr *pq.Error
-		// If Order exist in the DataBase
-		if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
-			return err
-		}
-		return fmt.Errorf("error during add order: %w", err)
+		return entities.UploadAccepted, fmt.Errorf("error during add order: %w", err)
 	}
 
-	return nil
+	switch {
+	case stored.Inserted:
+		return entities.UploadAccepted, nil
+	case stored.Owner == nil || *stored.Owner != order.UserID:
+		return entities.UploadOtherUser, nil
+	case stored.IsPreOrder:
+		return entities.UploadPreOrder, nil
+	default:
+		return entities.UploadAlreadyYours, nil
+	}
 }
 
-func (m *OrderService) GetOrders(ctx context.Context, userID uuid.UUID) (orders []entities.Order, err error) {
-	orders, err = m.stor.GetOrders(ctx, userID)
+// Bulk upload of orders in one batch, result for every order in the upload order.
+func (m *OrderService) AddOrders(ctx context.Context, userID uuid.UUID, orders []string) ([]entities.BatchResult, error) {
+	results := make([]entities.BatchResult, len(orders))
+	valid := make([]string, 0, len(orders))
+	for i, orderNr := range orders {
+		results[i].OrderNr = orderNr
+		if err := m.validator.Validate(orderNr); err != nil {
+			results[i].Result = entities.BatchInvalid
+			var orderErr *entities.OrderError
+			if errors.As(err, &orderErr) {
+				results[i].Reason = orderErr.Code
+			}
+			continue
+		}
+		valid = append(valid, orderNr)
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	batch, err := m.stor.AddOrders(ctx, userID, valid)
 	if err != nil {
 		return nil, err
 	}
-	return orders, nil
+	stored := make(map[string]entities.BatchOrder, len(batch))
+	for _, order := range batch {
+		stored[order.OrderNr] = order
+	}
+
+	// The same order twice in upload: first result as usual, next - already user's.
+	seen := make(map[string]bool)
+	for i := range results {
+		if results[i].Result != "" {
+			continue
+		}
+		order, ok := stored[results[i].OrderNr]
+		first := !seen[order.OrderNr]
+		seen[order.OrderNr] = true
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("order %s lost during bulk upload", results[i].OrderNr)
+		case order.Inserted && first:
+			results[i].Result = entities.BatchAccepted
+		case order.Inserted || (order.Owner != nil && *order.Owner == userID):
+			results[i].Result = entities.BatchAlreadyYours
+			results[i].PreOrder = order.IsPreOrder && first
+		default:
+			results[i].Result = entities.BatchOtherUser
+		}
+	}
+	return results, nil
 }
 
-func (m *OrderService) IsExistForOtherUser(ctx context.Context, userID uuid.UUID, order string) (isExist bool, err error) {
-	return m.stor.IsExistForOtherUser(ctx, userID, order)
+// Page of user's orders and cursor of the next page, nil for the last page.
+// Filter without limit returns all orders.
+func (m *OrderService) GetOrders(ctx context.Context, userID uuid.UUID, filter *entities.OrderFilter) (orders []entities.Order, next *entities.OrderCursor, err error) {
+	if filter.Limit == 0 {
+		orders, err = m.stor.GetOrders(ctx, userID, filter)
+		return orders, nil, err
+	}
+
+	// Load one more order to know if next page exists.
+	page := *filter
+	page.Limit++
+	orders, err = m.stor.GetOrders(ctx, userID, &page)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+		last := orders[len(orders)-1]
+		next = &entities.OrderCursor{Uploaded: last.Uploaded, OrderNr: last.OrderNr}
+	}
+	return orders, next, nil
 }
 
 func (m *OrderService) IsExist(ctx context.Context, order string) (isExist bool, err error) {
 	return m.stor.IsExist(ctx, order)
 }
+
+// User's order with status history.
+func (m *OrderService) GetOrder(ctx context.Context, userID uuid.UUID, orderNr string) (*entities.OrderDetail, error) {
+	order, err := m.stor.GetOrder(ctx, userID, orderNr)
+	if err != nil {
+		return nil, err
+	}
+	history, err := m.stor.GetOrderHistory(ctx, userID, orderNr)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.OrderDetail{Order: order, History: history}, nil
+}
+
+// Withdraw user's upload of new order, number becomes free for upload.
+func (m *OrderService) DeleteOrder(ctx context.Context, userID uuid.UUID, orderNr string) error {
+	return m.stor.DeleteOrder(ctx, userID, orderNr)
+}
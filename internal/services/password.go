@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Change of password and password reset with single-use tokens.
+type PasswordService struct {
+	stor     PasswordRepo
+	users    *UserService
+	notifier Notifier
+	resetTTL time.Duration
+}
+
+type PasswordRepo interface {
+	GetByLogin(ctx context.Context, login string) (*entities.User, error)
+	GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error)
+	SetPassword(ctx context.Context, userID uuid.UUID, hash string, keep uuid.UUID) error
+	AddPasswordReset(ctx context.Context, reset *entities.PasswordReset) error
+	ResetPassword(ctx context.Context, tokenHash string, hash string) (uuid.UUID, error)
+}
+
+// Delivery of reset tokens to users.
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, login string, token string, expires time.Time) error
+}
+
+func NewPasswordService(stor PasswordRepo, users *UserService, notifier Notifier, resetTTL time.Duration) *PasswordService {
+	return &PasswordService{stor: stor, users: users, notifier: notifier, resetTTL: resetTTL}
+}
+
+// Change password of user, other sessions of user are revoked.
+func (s *PasswordService) ChangePassword(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, old string, password string) error {
+	if err := s.users.ValidatePassword(password); err != nil {
+		return err
+	}
+	current, err := s.stor.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.users.CheckPassword(old, current) != nil {
+		return entities.ErrWrongPassword
+	}
+	hash, err := s.users.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.stor.SetPassword(ctx, userID, hash, sessionID)
+}
+
+// Send reset token to user in background. Caller gets the same answer in the same time
+// for known and unknown logins, errors are only logged.
+func (s *PasswordService) RequestReset(ctx context.Context, login string) {
+	go func(ctx context.Context, login string) {
+		err := s.sendReset(ctx, login)
+		if err != nil {
+			zap.S().Errorln("Can't send password reset: ", err)
+		}
+	}(context.WithoutCancel(ctx), login)
+}
+
+// Create reset token of user and send it. Unknown login is not an error.
+func (s *PasswordService) sendReset(ctx context.Context, login string) error {
+	user, err := s.stor.GetByLogin(ctx, entities.NormalizeLogin(login))
+	if err != nil {
+		zap.S().Infoln("Password reset for unknown login: ", login, err)
+		return nil
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	reset := &entities.PasswordReset{TokenHash: hash, UserID: user.UUID, Created: now, Expires: now.Add(s.resetTTL)}
+	err = s.stor.AddPasswordReset(ctx, reset)
+	if err != nil {
+		return err
+	}
+	return s.notifier.SendPasswordReset(ctx, login, token, reset.Expires)
+}
+
+// Set new password with reset token, all sessions of user are revoked.
+func (s *PasswordService) ConfirmReset(ctx context.Context, token string, password string) error {
+	if err := s.users.ValidatePassword(password); err != nil {
+		return err
+	}
+	hash, err := s.users.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	userID, err := s.stor.ResetPassword(ctx, hashToken(token), hash)
+	if err != nil {
+		return err
+	}
+	zap.S().Infoln("Password reset for user: ", userID)
+	return nil
+}
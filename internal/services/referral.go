@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Referral program: referrer and referee rewarded after referee's first processed order.
+type ReferralService struct {
+	stor          ReferralRepo
+	referrerBonus decimal.Decimal
+	refereeBonus  decimal.Decimal
+	limit         int
+}
+
+type ReferralRepo interface {
+	GetByReferralCode(ctx context.Context, code string) (userID uuid.UUID, err error)
+	GetReferralCode(ctx context.Context, userID uuid.UUID) (code string, err error)
+	AddReferral(ctx context.Context, referrerID uuid.UUID, refereeID uuid.UUID) error
+	GetReferrals(ctx context.Context, referrerID uuid.UUID) ([]entities.Referral, error)
+	RewardReferral(ctx context.Context, refereeID uuid.UUID, referrerBonus, refereeBonus decimal.Decimal, limit int) (*entities.Referral, error)
+}
+
+func NewReferralService(stor ReferralRepo, referrerBonus, refereeBonus decimal.Decimal, limit int) *ReferralService {
+	return &ReferralService{stor: stor, referrerBonus: referrerBonus, refereeBonus: refereeBonus, limit: limit}
+}
+
+// Find referrer by code, case is ignored. Return entities.ErrReferralNotFound for unknown code.
+func (r *ReferralService) GetReferrer(ctx context.Context, code string) (referrerID uuid.UUID, err error) {
+	// Codes are stored upper-case.
+	return r.stor.GetByReferralCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func (r *ReferralService) AddReferral(ctx context.Context, referrerID uuid.UUID, refereeID uuid.UUID) error {
+	return r.stor.AddReferral(ctx, referrerID, refereeID)
+}
+
+// Reward referrer and referee, if user was invited and it is first reward.
+func (r *ReferralService) Reward(ctx context.Context, refereeID uuid.UUID) {
+	referral, err := r.stor.RewardReferral(ctx, refereeID, r.referrerBonus, r.refereeBonus, r.limit)
+	if err != nil {
+		zap.S().Errorln("Can't reward referral: ", err)
+		return
+	}
+	if referral != nil {
+		zap.S().Infoln("Referral rewarded, referee: ", refereeID, " status: ", referral.Status)
+	}
+}
+
+// User's referral code, invited users and earned rewards.
+func (r *ReferralService) GetReferrals(ctx context.Context, userID uuid.UUID) (*entities.ReferralsInfo, error) {
+	code, err := r.stor.GetReferralCode(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	referrals, err := r.stor.GetReferrals(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get referrals: %w", err)
+	}
+
+	earned := decimal.Zero
+	for _, referral := range referrals {
+		earned = earned.Add(referral.ReferrerBonus)
+	}
+
+	return &entities.ReferralsInfo{Code: code, Earned: earned.InexactFloat64(), Referrals: referrals}, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/entities"
+)
+
+// Length of random refresh and reset tokens, bytes.
+const tokenLength = 32
+
+// Login sessions: short-lived access JWT and rotating refresh tokens.
+type SessionService struct {
+	stor       SessionRepo
+	keys       *entities.JWTKeys
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+type SessionRepo interface {
+	AddSession(ctx context.Context, session *entities.Session) error
+	RotateSession(ctx context.Context, refreshHash string, newHash string, expires time.Time) (*entities.Session, error)
+	IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID) error
+}
+
+func NewSessionService(stor SessionRepo, keys *entities.JWTKeys, accessTTL time.Duration, refreshTTL time.Duration) *SessionService {
+	return &SessionService{stor: stor, keys: keys, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// Start new session for user.
+func (s *SessionService) Login(ctx context.Context, userID uuid.UUID) (*entities.TokenPair, error) {
+	refresh, hash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := &entities.Session{UserID: userID, RefreshHash: hash, Created: now, Expires: now.Add(s.refreshTTL)}
+	err = s.stor.AddSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(session, refresh)
+}
+
+// Exchange refresh token for new token pair, presented token is not valid after it.
+func (s *SessionService) Refresh(ctx context.Context, refresh string) (*entities.TokenPair, error) {
+	next, hash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.stor.RotateSession(ctx, hashToken(refresh), hash, time.Now().Add(s.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(session, next)
+}
+
+func (s *SessionService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	return s.stor.RevokeSession(ctx, sessionID)
+}
+
+// Public keys for verification of access tokens by other services.
+func (s *SessionService) JWKS() entities.JWKSet {
+	return s.keys.JWKS()
+}
+
+// User, session and role of access token, session must be active.
+func (s *SessionService) Authenticate(ctx context.Context, token string) (userID uuid.UUID, sessionID uuid.UUID, role string, err error) {
+	claims, err := ParseJWT(token, s.keys)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "", err
+	}
+	sessionID, err = uuid.FromString(claims.ID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "", entities.ErrSessionNotFound
+	}
+	active, err := s.stor.IsSessionActive(ctx, sessionID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "", err
+	}
+	if !active {
+		return uuid.Nil, uuid.Nil, "", entities.ErrSessionRevoked
+	}
+	return claims.UserID, sessionID, claims.Role, nil
+}
+
+func (s *SessionService) issue(session *entities.Session, refresh string) (*entities.TokenPair, error) {
+	access, err := BuildJWTString(session.UserID, session.SessionID, session.Role, s.keys, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.TokenPair{AccessToken: access, RefreshToken: refresh, TokenType: "Bearer", ExpiresIn: int64(s.accessTTL.Seconds())}, nil
+}
+
+// Random token and its hash for storage.
+func newToken() (token string, hash string, err error) {
+	b := make([]byte, tokenLength)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Loyalty tiers: user's tier computed from accruals in rolling window.
+type TierService struct {
+	stor   TierRepo
+	tiers  []entities.Tier
+	window time.Duration
+}
+
+type TierRepo interface {
+	GetTier(ctx context.Context, userID uuid.UUID) (tier string, err error)
+	SetTier(ctx context.Context, change *entities.TierChange) error
+	GetTierHistory(ctx context.Context, userID uuid.UUID) ([]entities.TierChange, error)
+	GetLifetimeAccruals(ctx context.Context, userID uuid.UUID, since time.Time) (lifetime decimal.Decimal, err error)
+}
+
+// Tiers must be sorted by threshold, the first tier with zero threshold.
+func NewTierService(stor TierRepo, tiers []entities.Tier, window time.Duration) *TierService {
+	return &TierService{stor: stor, tiers: tiers, window: window}
+}
+
+// Recompute user's tier and record it if changed. Return current user's tier.
+func (t *TierService) UpdateTier(ctx context.Context, userID uuid.UUID) (tier entities.Tier, lifetime decimal.Decimal, err error) {
+	lifetime, err = t.stor.GetLifetimeAccruals(ctx, userID, time.Now().Add(-t.window))
+	if err != nil {
+		return entities.Tier{}, decimal.Zero, err
+	}
+
+	tier = t.tierFor(lifetime)
+
+	current, err := t.stor.GetTier(ctx, userID)
+	if err != nil {
+		return entities.Tier{}, decimal.Zero, err
+	}
+
+	if current != tier.Name {
+		change := &entities.TierChange{UserID: userID, From: current, To: tier.Name, Lifetime: lifetime, Changed: time.Now()}
+		err = t.stor.SetTier(ctx, change)
+		if err != nil {
+			return entities.Tier{}, decimal.Zero, err
+		}
+		zap.S().Infoln("User ", userID, " tier changed from ", current, " to ", tier.Name)
+	}
+
+	return tier, lifetime, nil
+}
+
+// Accrual multiplier of user's tier.
+func (t *TierService) Multiplier(ctx context.Context, userID uuid.UUID) (tier entities.Tier, err error) {
+	tier, _, err = t.UpdateTier(ctx, userID)
+	if err != nil {
+		return entities.Tier{}, fmt.Errorf("can't get user's tier: %w", err)
+	}
+	return tier, nil
+}
+
+// User's tier, progress to the next tier and tier changes.
+func (t *TierService) GetProfile(ctx context.Context, userID uuid.UUID) (*entities.Profile, error) {
+	tier, lifetime, err := t.UpdateTier(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't update user's tier: %w", err)
+	}
+
+	history, err := t.stor.GetTierHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &entities.Profile{
+		Tier:       tier.Name,
+		Multiplier: tier.Multiplier.InexactFloat64(),
+		Lifetime:   lifetime.InexactFloat64(),
+		History:    history,
+	}
+
+	if next, ok := t.nextTier(tier); ok {
+		profile.NextTier = next.Name
+		profile.ToNextTier = next.Threshold.Sub(lifetime).InexactFloat64()
+	}
+
+	return profile, nil
+}
+
+func (t *TierService) tierFor(lifetime decimal.Decimal) entities.Tier {
+	tier := t.tiers[0]
+	for _, candidate := range t.tiers {
+		if lifetime.GreaterThanOrEqual(candidate.Threshold) {
+			tier = candidate
+		}
+	}
+	return tier
+}
+
+func (t *TierService) nextTier(tier entities.Tier) (entities.Tier, bool) {
+	for _, candidate := range t.tiers {
+		if candidate.Threshold.GreaterThan(tier.Threshold) {
+			return candidate, true
+		}
+	}
+	return entities.Tier{}, false
+}
@@ -5,21 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/jackc/pgerrcode"
 	"github.com/lib/pq"
-	"github.com/shulganew/gophermart/internal/app/config"
 	"github.com/shulganew/gophermart/internal/entities"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Hash checked when login not found, so answer takes the same time as for wrong password.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("gophermart"), bcrypt.DefaultCost)
+
 // User creation, registration, validation and autentification service.
 type UserService struct {
-	stor UserRepo
+	stor   UserRepo
+	policy entities.CredentialPolicy
 }
 
 type UserRepo interface {
@@ -27,12 +31,17 @@ type UserRepo interface {
 	GetByLogin(ctx context.Context, login string) (*entities.User, error)
 }
 
-func NewUserService(stor UserRepo) *UserService {
-	return &UserService{stor: stor}
+func NewUserService(stor UserRepo, policy entities.CredentialPolicy) *UserService {
+	return &UserService{stor: stor, policy: policy}
 }
 
-// Register new user in market.
+// Register new user in market, login and password are checked with policy.
 func (r *UserService) CreateUser(ctx context.Context, login string, password string) (userID *uuid.UUID, existed bool, err error) {
+	login, err = r.policy.Validate(login, password)
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Set hash as user password.
 	hash, err := r.HashPassword(password)
 	if err != nil {
@@ -58,10 +67,11 @@ func (r *UserService) CreateUser(ctx context.Context, login string, password str
 // Validate user in market, if sucsess it return user's id.
 func (r *UserService) IsValid(ctx context.Context, login string, pass string) (userID *uuid.UUID, isValid bool) {
 	// Get User from storage
-	user, err := r.stor.GetByLogin(ctx, login)
+	user, err := r.stor.GetByLogin(ctx, entities.NormalizeLogin(login))
 	zap.S().Infof("User form db: %v \n", user)
 	if err != nil {
 		zap.S().Infoln("User not found by login. ", err)
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(pass))
 		return nil, false
 	}
 
@@ -75,6 +85,11 @@ func (r *UserService) IsValid(ctx context.Context, login string, pass string) (u
 	return &user.UUID, true
 }
 
+// Check new password of existing user with policy.
+func (r *UserService) ValidatePassword(password string) error {
+	return r.policy.ValidatePassword(password)
+}
+
 // HashPassword returns the bcrypt hash of the password.
 func (r UserService) HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -89,16 +104,21 @@ func (r UserService) CheckPassword(password string, hashedPassword string) error
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
-// Create JWT token.
-func BuildJWTString(userID uuid.UUID, pass string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, entities.Claims{
+// Create access JWT token of user's session, session id is token's jti.
+func BuildJWTString(userID uuid.UUID, sessionID uuid.UUID, role string, keys *entities.JWTKeys, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(keys.Method, entities.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.TokenExp)),
+			ID:        sessionID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 		},
 		UserID: userID,
+		Role:   role,
 	})
+	if keys.SignID != "" {
+		token.Header["kid"] = keys.SignID
+	}
 
-	tokenString, err := token.SignedString([]byte(pass))
+	tokenString, err := token.SignedString(keys.Sign)
 	if err != nil {
 		return "", err
 	}
@@ -106,29 +126,30 @@ func BuildJWTString(userID uuid.UUID, pass string) (string, error) {
 	return tokenString, nil
 }
 
-// Retrive user's UUID from JWT string.
-func GetUserIDJWT(tokenString string, pass string) (userID uuid.UUID, err error) {
+// Retrive claims from JWT string, token must be signed with one of verification keys.
+func ParseJWT(tokenString string, keys *entities.JWTKeys) (*entities.Claims, error) {
 	claims := &entities.Claims{}
-	_, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-		return []byte(pass), nil
-	})
-
-	return claims.UserID, err
-}
-
-// Create jwt token from string.
-func GetJWT(tokenString string, pass string) (token *jwt.Token, err error) {
-	claims := &entities.Claims{}
-	token, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-		return []byte(pass), nil
-	})
-
-	return token, err
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := keys.Verify[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown JWT key: %q", kid)
+		}
+		// Key is bound to its method, alg of token can't switch it.
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("wrong JWT signing method %s for key %q", t.Method.Alg(), kid)
+		}
+		return key.Key, nil
+	}, jwt.WithValidMethods(keys.Methods()))
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
 
-// Check JWT is Set to Header.
+// Check JWT is Set to Header, Bearer prefix is optional.
 func GetHeaderJWT(header http.Header) (jwt string, isSet bool) {
-	auth := header.Get("Authorization")
+	auth := strings.TrimPrefix(header.Get("Authorization"), "Bearer ")
 	if auth == "" {
 		return "", false
 	}
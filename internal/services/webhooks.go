@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shulganew/gophermart/internal/app/config"
+	"github.com/shulganew/gophermart/internal/entities"
+	"go.uber.org/zap"
+)
+
+// Delivery retries: backoff doubles from first retry delay, delivery fails after max attempts.
+// Each delivery is leased only for its own send, so the lease can't expire while batch is sent.
+const (
+	webhookFirstRetry  = 30 * time.Second
+	webhookMaxAttempts = 8
+	webhookBatch       = 100
+	webhookTimeout     = 10 * time.Second
+	webhookLease       = webhookTimeout * 2
+	webhookDeliveries  = 100
+)
+
+// Outbound webhooks: deliveries are saved by storage in transaction of order's status or withdrawal change
+// and sent by worker with retries, at-least-once.
+type WebhookService struct {
+	stor   WebhookRepo
+	client *http.Client
+}
+
+type WebhookRepo interface {
+	AddWebhook(ctx context.Context, webhook *entities.Webhook) error
+	GetWebhooks(ctx context.Context, userID uuid.UUID) ([]entities.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) error
+	TakeDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entities.Delivery, error)
+	UpdateDelivery(ctx context.Context, delivery *entities.Delivery) error
+	GetDeliveries(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, limit int) ([]entities.Delivery, error)
+}
+
+func NewWebhookService(stor WebhookRepo, allowPrivate bool) *WebhookService {
+	return &WebhookService{stor: stor, client: newWebhookClient(allowPrivate)}
+}
+
+// Client of deliveries dials only public addresses, unless private targets are allowed.
+// Address is checked after DNS resolution, so host can't be rebound to internal address.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !entities.IsPublicIP(ip) {
+				return fmt.Errorf("%w: %s", entities.ErrWebhookTarget, address)
+			}
+			return nil
+		}
+	}
+	// Proxy is not used, target address is dialed directly.
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+		MaxIdleConns:        webhookBatch,
+		IdleConnTimeout:     webhookTimeout * 6,
+	}
+	return &http.Client{Timeout: webhookTimeout, Transport: transport}
+}
+
+// Run delivery worker.
+func (w *WebhookService) Run(ctx context.Context) {
+	deliver := time.NewTicker(config.CheckWebhooks * time.Second)
+	go func(ctx context.Context, w *WebhookService) {
+		for {
+			select {
+			case <-ctx.Done():
+				deliver.Stop()
+				return
+			case <-deliver.C:
+				w.Deliver(ctx)
+			}
+		}
+	}(ctx, w)
+}
+
+// Send up to webhookBatch due deliveries, each one is taken with its own lease.
+func (w *WebhookService) Deliver(ctx context.Context) {
+	for i := 0; i < webhookBatch; i++ {
+		deliveries, err := w.stor.TakeDeliveries(ctx, 1, webhookLease)
+		if err != nil {
+			zap.S().Errorln("Can't load webhook deliveries: ", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		delivery := &deliveries[0]
+		w.send(ctx, delivery)
+		err = w.stor.UpdateDelivery(ctx, delivery)
+		if err != nil {
+			zap.S().Errorln("Can't save webhook delivery: ", err)
+		}
+	}
+}
+
+func (w *WebhookService) AddWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	return w.stor.AddWebhook(ctx, webhook)
+}
+
+func (w *WebhookService) GetWebhooks(ctx context.Context, userID uuid.UUID) ([]entities.Webhook, error) {
+	return w.stor.GetWebhooks(ctx, userID)
+}
+
+func (w *WebhookService) DeleteWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) error {
+	return w.stor.DeleteWebhook(ctx, userID, webhookID)
+}
+
+// Recent deliveries of user's webhook.
+func (w *WebhookService) GetDeliveries(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) ([]entities.Delivery, error) {
+	return w.stor.GetDeliveries(ctx, userID, webhookID, webhookDeliveries)
+}
+
+// POST signed payload, update delivery with result of attempt.
+func (w *WebhookService) send(ctx context.Context, delivery *entities.Delivery) {
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(entities.WebhookEventHeader, delivery.Event)
+		req.Header.Set(entities.WebhookDeliveryHeader, delivery.DeliveryID.String())
+		req.Header.Set(entities.WebhookTimestampHeader, timestamp)
+		req.Header.Set(entities.WebhookSignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+		var res *http.Response
+		res, err = w.client.Do(req)
+		if err == nil {
+			_ = res.Body.Close()
+			code := res.StatusCode
+			delivery.ResponseCode = &code
+			if code < 200 || code >= 300 {
+				err = fmt.Errorf("response status %d", code)
+			}
+		}
+	}
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = entities.DeliveryDelivered
+		delivery.Delivered = &now
+		delivery.Error = ""
+		return
+	}
+
+	delivery.Error = err.Error()
+	// Not public target is not retried.
+	if delivery.Attempts >= webhookMaxAttempts || errors.Is(err, entities.ErrWebhookTarget) {
+		delivery.Status = entities.DeliveryFailed
+		zap.S().Infoln("Webhook delivery failed: ", delivery.DeliveryID, " ", err)
+		return
+	}
+	delivery.NextAttempt = time.Now().Add(webhookFirstRetry << (delivery.Attempts - 1))
+}
+
+// Signature of webhook request: hex HMAC-SHA256 of "timestamp.payload" with webhook's secret.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
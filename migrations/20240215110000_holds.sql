@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'hold_status') THEN
+		CREATE TYPE hold_status AS ENUM ('HELD', 'CAPTURED', 'VOIDED', 'EXPIRED');
+	END IF;
+END$$;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS held NUMERIC DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS holds (
+		id SERIAL,
+		hold_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		amount NUMERIC NOT NULL,
+		status hold_status NOT NULL DEFAULT 'HELD',
+		order_number VARCHAR(20),
+		created TIMESTAMPTZ NOT NULL,
+		expires TIMESTAMPTZ NOT NULL
+		);
+
+CREATE INDEX IF NOT EXISTS holds_status_expires_idx ON holds (status, expires);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE holds;
+ALTER TABLE users DROP COLUMN held;
+DROP TYPE hold_status;
+-- +goose StatementEnd
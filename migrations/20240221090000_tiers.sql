@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ledger (
+		id SERIAL,
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		order_number VARCHAR(20),
+		kind TEXT NOT NULL,
+		amount NUMERIC NOT NULL,
+		label TEXT NOT NULL DEFAULT '',
+		created TIMESTAMPTZ NOT NULL
+		);
+
+CREATE INDEX IF NOT EXISTS ledger_user_created_idx ON ledger (user_id, created);
+
+-- Fill ledger with accruals and withdrawals made before it.
+INSERT INTO ledger (user_id, order_number, kind, amount, created)
+SELECT user_id, order_number, 'ACCRUAL', accrual, uploaded
+FROM orders
+WHERE status = 'PROCESSED' AND accrual > 0;
+
+INSERT INTO ledger (user_id, order_number, kind, amount, created)
+SELECT user_id, order_number, 'WITHDRAWAL', -withdrawn, uploaded
+FROM orders
+WHERE withdrawn > 0;
+
+INSERT INTO ledger (user_id, kind, amount, label, created)
+SELECT user_id, 'HOLD', -amount, hold_id::TEXT, created
+FROM holds
+WHERE status = 'HELD';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tier TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS tier_history (
+		id SERIAL,
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		tier_from TEXT NOT NULL,
+		tier_to TEXT NOT NULL,
+		lifetime NUMERIC NOT NULL,
+		changed TIMESTAMPTZ NOT NULL
+		);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE tier_history;
+ALTER TABLE users DROP COLUMN tier;
+DROP TABLE ledger;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS campaigns (
+		id SERIAL,
+		campaign_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		name TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		value NUMERIC NOT NULL,
+		first_order BOOLEAN NOT NULL DEFAULT FALSE,
+		min_accrual NUMERIC NOT NULL DEFAULT 0,
+		stackable BOOLEAN NOT NULL DEFAULT FALSE,
+		starts TIMESTAMPTZ NOT NULL,
+		ends TIMESTAMPTZ NOT NULL,
+		paused BOOLEAN NOT NULL DEFAULT FALSE,
+		created TIMESTAMPTZ NOT NULL
+		);
+
+CREATE TABLE IF NOT EXISTS campaign_credits (
+		id SERIAL,
+		campaign_id UUID NOT NULL REFERENCES campaigns(campaign_id),
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		order_number VARCHAR(20) NOT NULL,
+		amount NUMERIC NOT NULL,
+		created TIMESTAMPTZ NOT NULL,
+		UNIQUE (campaign_id, order_number)
+		);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE campaign_credits;
+DROP TABLE campaigns;
+-- +goose StatementEnd
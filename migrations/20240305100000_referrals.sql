@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code TEXT UNIQUE DEFAULT upper(substr(md5(random()::TEXT), 1, 8));
+UPDATE users SET referral_code = upper(substr(md5(user_id::TEXT || random()::TEXT), 1, 8)) WHERE referral_code IS NULL;
+
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'referral_status') THEN
+		CREATE TYPE referral_status AS ENUM ('PENDING', 'REWARDED', 'CAPPED');
+	END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS referrals (
+		id SERIAL,
+		referrer_id UUID NOT NULL REFERENCES users(user_id),
+		referee_id UUID NOT NULL UNIQUE REFERENCES users(user_id),
+		status referral_status NOT NULL DEFAULT 'PENDING',
+		referrer_bonus NUMERIC NOT NULL DEFAULT 0,
+		referee_bonus NUMERIC NOT NULL DEFAULT 0,
+		created TIMESTAMPTZ NOT NULL,
+		rewarded TIMESTAMPTZ
+		);
+
+CREATE INDEX IF NOT EXISTS referrals_referrer_idx ON referrals (referrer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE referrals;
+DROP TYPE referral_status;
+ALTER TABLE users DROP COLUMN referral_code;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Users registered before migration have no registration cool-down.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS registered TIMESTAMPTZ NOT NULL DEFAULT 'epoch';
+ALTER TABLE users ALTER COLUMN registered SET DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN registered;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'adjustment_status') THEN
+		CREATE TYPE adjustment_status AS ENUM ('PENDING', 'APPLIED', 'REJECTED');
+	END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS adjustments (
+		id SERIAL,
+		adjustment_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		amount NUMERIC NOT NULL,
+		reason TEXT NOT NULL,
+		note TEXT NOT NULL,
+		status adjustment_status NOT NULL,
+		created_by TEXT NOT NULL,
+		decided_by TEXT,
+		created TIMESTAMPTZ NOT NULL,
+		decided TIMESTAMPTZ
+		);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+		id SERIAL,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		user_id UUID,
+		details JSONB NOT NULL DEFAULT '{}',
+		created TIMESTAMPTZ NOT NULL
+		);
+
+CREATE INDEX IF NOT EXISTS audit_log_user_idx ON audit_log (user_id, created);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_log;
+DROP TABLE adjustments;
+DROP TYPE adjustment_status;
+-- +goose StatementEnd
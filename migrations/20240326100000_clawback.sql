@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Time of processing, orders processed before migration use upload time.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS processed TIMESTAMPTZ;
+UPDATE orders SET processed = uploaded WHERE status = 'PROCESSED';
+
+CREATE INDEX IF NOT EXISTS orders_processed_idx ON orders (processed) WHERE status = 'PROCESSED';
+CREATE INDEX IF NOT EXISTS ledger_order_idx ON ledger (order_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX ledger_order_idx;
+ALTER TABLE orders DROP COLUMN processed;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS orders_user_uploaded_idx ON orders (user_id, uploaded, order_number) WHERE is_preorder = FALSE;
+CREATE INDEX IF NOT EXISTS orders_user_status_idx ON orders (user_id, status, uploaded, order_number) WHERE is_preorder = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX orders_user_status_idx;
+DROP INDEX orders_user_uploaded_idx;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_status_history (
+		id SERIAL,
+		order_number VARCHAR(20) NOT NULL REFERENCES orders(order_number) ON DELETE CASCADE,
+		status processing NOT NULL,
+		accrual NUMERIC,
+		source TEXT NOT NULL,
+		created TIMESTAMPTZ NOT NULL
+		);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_idx ON order_status_history (order_number, created);
+
+-- Orders uploaded before migration get upload and current status.
+INSERT INTO order_status_history (order_number, status, source, created)
+SELECT order_number, 'NEW', 'upload', uploaded FROM orders;
+
+INSERT INTO order_status_history (order_number, status, accrual, source, created)
+SELECT order_number, status, NULLIF(accrual, 0), 'migration', COALESCE(processed, uploaded) FROM orders WHERE status != 'NEW';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE order_status_history;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'delivery_status') THEN
+		CREATE TYPE delivery_status AS ENUM ('PENDING', 'DELIVERED', 'FAILED');
+	END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS webhooks (
+		id SERIAL,
+		webhook_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT[] NOT NULL,
+		created TIMESTAMPTZ NOT NULL
+		);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id SERIAL,
+		delivery_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		webhook_id UUID NOT NULL REFERENCES webhooks(webhook_id) ON DELETE CASCADE,
+		event TEXT NOT NULL,
+		payload JSONB NOT NULL,
+		status delivery_status NOT NULL DEFAULT 'PENDING',
+		attempts INT NOT NULL DEFAULT 0,
+		next_attempt TIMESTAMPTZ NOT NULL,
+		response_code INT,
+		error TEXT NOT NULL DEFAULT '',
+		created TIMESTAMPTZ NOT NULL,
+		delivered TIMESTAMPTZ
+		);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_idx ON webhooks (user_id);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+DROP TYPE delivery_status;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'dispute_status') THEN
+		CREATE TYPE dispute_status AS ENUM ('OPEN', 'REASSIGNED', 'REJECTED');
+	END IF;
+END$$;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS disputed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS disputes (
+		id SERIAL,
+		dispute_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		order_number VARCHAR(20) NOT NULL REFERENCES orders(order_number),
+		claimant UUID NOT NULL REFERENCES users(user_id),
+		owner UUID NOT NULL REFERENCES users(user_id),
+		evidence TEXT NOT NULL,
+		status dispute_status NOT NULL DEFAULT 'OPEN',
+		moved NUMERIC NOT NULL DEFAULT 0,
+		note TEXT,
+		decided_by TEXT,
+		created TIMESTAMPTZ NOT NULL,
+		decided TIMESTAMPTZ
+		);
+
+-- One open dispute for order.
+CREATE UNIQUE INDEX IF NOT EXISTS disputes_open_idx ON disputes (order_number) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS disputes_claimant_idx ON disputes (claimant, created);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE disputes;
+ALTER TABLE orders DROP COLUMN disputed;
+DROP TYPE dispute_status;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- New enum value can't be used in the transaction where it is added.
+ALTER TYPE processing ADD VALUE IF NOT EXISTS 'DELETED';
+
+-- +goose StatementBegin
+-- History and disputes outlive deleted orders, history belongs to the owner of the upload.
+ALTER TABLE order_status_history ADD COLUMN IF NOT EXISTS user_id UUID;
+UPDATE order_status_history h SET user_id = o.user_id FROM orders o WHERE h.order_number = o.order_number;
+ALTER TABLE order_status_history DROP CONSTRAINT IF EXISTS order_status_history_order_number_fkey;
+ALTER TABLE disputes DROP CONSTRAINT IF EXISTS disputes_order_number_fkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM order_status_history h WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_number = h.order_number);
+ALTER TABLE order_status_history ADD CONSTRAINT order_status_history_order_number_fkey FOREIGN KEY (order_number) REFERENCES orders(order_number) ON DELETE CASCADE;
+ALTER TABLE order_status_history DROP COLUMN user_id;
+ALTER TABLE disputes ADD CONSTRAINT disputes_order_number_fkey FOREIGN KEY (order_number) REFERENCES orders(order_number) NOT VALID;
+-- Enum value DELETED can't be dropped.
+-- +goose StatementEnd
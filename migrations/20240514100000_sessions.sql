@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS sessions (
+		id SERIAL,
+		session_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		refresh_hash TEXT NOT NULL UNIQUE,
+		previous_hash TEXT,
+		created TIMESTAMPTZ NOT NULL,
+		refreshed TIMESTAMPTZ,
+		expires TIMESTAMPTZ NOT NULL,
+		revoked TIMESTAMPTZ
+		);
+
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS sessions_previous_idx ON sessions (previous_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sessions;
+-- +goose StatementEnd
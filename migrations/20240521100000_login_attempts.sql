@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_attempts (
+		scope TEXT NOT NULL,
+		key TEXT NOT NULL,
+		failures INT NOT NULL DEFAULT 0,
+		last_failure TIMESTAMPTZ,
+		locked_until TIMESTAMPTZ,
+		PRIMARY KEY (scope, key)
+		);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE login_attempts;
+-- +goose StatementEnd
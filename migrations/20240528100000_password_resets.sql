@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS password_resets (
+		id SERIAL,
+		token_hash TEXT NOT NULL UNIQUE,
+		user_id UUID NOT NULL REFERENCES users(user_id),
+		created TIMESTAMPTZ NOT NULL,
+		expires TIMESTAMPTZ NOT NULL,
+		used TIMESTAMPTZ
+		);
+
+CREATE INDEX IF NOT EXISTS password_resets_user_idx ON password_resets (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE password_resets;
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed;
+-- +goose StatementEnd
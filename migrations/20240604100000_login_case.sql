@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Logins are unique ignoring case and Unicode form. Existing logins which differ only in case or form
+-- can't be merged automatically, migration fails with the list of them to be renamed.
+DO $$
+DECLARE
+	duplicates TEXT;
+BEGIN
+	SELECT string_agg(logins, '; ') INTO duplicates
+	FROM (
+		SELECT string_agg(login, ', ' ORDER BY login) AS logins
+		FROM users
+		GROUP BY lower(btrim(normalize(login, NFKC), E' \t\n\r\f'))
+		HAVING count(*) > 1
+	) d;
+	IF duplicates IS NOT NULL THEN
+		RAISE EXCEPTION 'logins differ only in case or Unicode form, rename them before migration: %', duplicates;
+	END IF;
+END $$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Stored logins in NFKC form without surrounding spaces, as new logins. Case is kept, lookups ignore it.
+UPDATE users
+SET login = btrim(normalize(login, NFKC), E' \t\n\r\f')
+WHERE login <> btrim(normalize(login, NFKC), E' \t\n\r\f');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX IF NOT EXISTS users_login_lower_idx ON users (lower(login));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Normalization of logins is not reverted.
+DROP INDEX IF EXISTS users_login_lower_idx;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS merchant_keys (
+		id SERIAL,
+		key_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+		merchant TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		scopes TEXT[] NOT NULL,
+		created_by TEXT NOT NULL,
+		created TIMESTAMPTZ NOT NULL,
+		expires TIMESTAMPTZ,
+		last_used TIMESTAMPTZ,
+		revoked TIMESTAMPTZ
+		);
+
+-- Orders uploaded by merchant with API key.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS merchant_key_id UUID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS merchant_key_id;
+DROP TABLE merchant_keys;
+-- +goose StatementEnd